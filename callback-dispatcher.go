@@ -1,46 +1,167 @@
 package fbmessenger
 
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"strings"
+)
+
 // MessageEntryHandler functions are for handling individual interactions with a user.
 type MessageEntryHandler func(cb *MessagingEntry) error
 
+// ContextMessageEntryHandler functions are like MessageEntryHandler functions but also
+// receive the context passed to DispatchWithContext and the Id of the page the
+// interaction was with.
+type ContextMessageEntryHandler func(ctx context.Context, pageId string, cb *MessagingEntry) error
+
+// DispatchErrorPolicy determines what Dispatch does when a handler returns an error.
+type DispatchErrorPolicy int
+
+const (
+	// ContinueOnError dispatches every MessagingEntry in the callback and returns all
+	// handler errors together. This is the default.
+	ContinueOnError DispatchErrorPolicy = iota
+
+	// StopOnFirstError stops dispatching at the first handler that returns an error.
+	StopOnFirstError
+)
+
 /*
 CallbackDispatcher routes each MessagingEntry included in a callback to an appropriate
 handler for the type of entry. Note that due to webhook batching, a handler may be called
 more than once per callback.
+
+Each type of entry has a MessageEntryHandler and a ContextMessageEntryHandler. When both
+are set, only the ContextMessageEntryHandler is called.
 */
 type CallbackDispatcher struct {
 	MessageHandler        MessageEntryHandler
 	DeliveryHandler       MessageEntryHandler
+	ReadHandler           MessageEntryHandler
 	PostbackHandler       MessageEntryHandler
 	AuthenticationHandler MessageEntryHandler
+
+	MessageContextHandler        ContextMessageEntryHandler
+	DeliveryContextHandler       ContextMessageEntryHandler
+	ReadContextHandler           ContextMessageEntryHandler
+	PostbackContextHandler       ContextMessageEntryHandler
+	AuthenticationContextHandler ContextMessageEntryHandler
+
+	ErrorPolicy DispatchErrorPolicy
 }
 
 /*
 Dispatch routes each MessagingEntry included in the callback to an appropriate
-handler for the type of entry.
+handler for the type of entry. If any handler returns an error, Dispatch returns
+a *DispatchError identifying each failed entry.
 */
 func (dispatcher *CallbackDispatcher) Dispatch(cb *Callback) error {
+	return dispatcher.DispatchWithContext(context.Background(), cb)
+}
+
+// DispatchWithContext is like Dispatch but passes ctx through to each handler.
+func (dispatcher *CallbackDispatcher) DispatchWithContext(ctx context.Context, cb *Callback) error {
+	dispatchErr := &DispatchError{}
+
 	for _, entry := range cb.Entries {
 		for _, messagingEntry := range entry.Messaging {
-			if messagingEntry.Message != nil {
-				if dispatcher.MessageHandler != nil {
-					dispatcher.MessageHandler(messagingEntry)
-				}
-			} else if messagingEntry.Delivery != nil {
-				if dispatcher.DeliveryHandler != nil {
-					dispatcher.DeliveryHandler(messagingEntry)
-				}
-			} else if messagingEntry.Postback != nil {
-				if dispatcher.PostbackHandler != nil {
-					dispatcher.PostbackHandler(messagingEntry)
-				}
-			} else if messagingEntry.OptIn != nil {
-				if dispatcher.AuthenticationHandler != nil {
-					dispatcher.AuthenticationHandler(messagingEntry)
+			handler := dispatcher.handlerFor(messagingEntry)
+			if handler == nil {
+				continue
+			}
+
+			err := handler(ctx, entry.PageId, messagingEntry)
+			if err != nil {
+				dispatchErr.Errors = append(dispatchErr.Errors, &HandlerError{
+					Entry:          entry,
+					MessagingEntry: messagingEntry,
+					Err:            err,
+				})
+
+				if dispatcher.ErrorPolicy == StopOnFirstError {
+					return dispatchErr
 				}
 			}
 		}
 	}
 
+	if len(dispatchErr.Errors) > 0 {
+		return dispatchErr
+	}
+
+	return nil
+}
+
+func (dispatcher *CallbackDispatcher) handlerFor(messagingEntry *MessagingEntry) ContextMessageEntryHandler {
+	if messagingEntry.Message != nil {
+		return chooseHandler(dispatcher.MessageContextHandler, dispatcher.MessageHandler)
+	} else if messagingEntry.Delivery != nil {
+		return chooseHandler(dispatcher.DeliveryContextHandler, dispatcher.DeliveryHandler)
+	} else if messagingEntry.Read != nil {
+		return chooseHandler(dispatcher.ReadContextHandler, dispatcher.ReadHandler)
+	} else if messagingEntry.Postback != nil {
+		return chooseHandler(dispatcher.PostbackContextHandler, dispatcher.PostbackHandler)
+	} else if messagingEntry.OptIn != nil {
+		return chooseHandler(dispatcher.AuthenticationContextHandler, dispatcher.AuthenticationHandler)
+	}
+
+	return nil
+}
+
+func chooseHandler(contextHandler ContextMessageEntryHandler, handler MessageEntryHandler) ContextMessageEntryHandler {
+	if contextHandler != nil {
+		return contextHandler
+	}
+
+	if handler != nil {
+		return func(ctx context.Context, pageId string, cb *MessagingEntry) error {
+			return handler(cb)
+		}
+	}
+
 	return nil
 }
+
+// HandlerError holds the error returned by a handler along with the entries it was handling.
+type HandlerError struct {
+	Entry          *Entry
+	MessagingEntry *MessagingEntry
+	Err            error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("error handling entry for page %v from sender %v: %v", e.Entry.PageId, e.MessagingEntry.Sender.Id, e.Err)
+}
+
+// Unwrap returns the error returned by the handler.
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// DispatchError is returned by Dispatch when one or more handlers return an error.
+type DispatchError struct {
+	Errors []*HandlerError
+}
+
+func (e *DispatchError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%v errors dispatching callback: %v", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the error of each handler that failed.
+func (e *DispatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+
+	return errs
+}
@@ -5,12 +5,16 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"errors"
+	"golang.org/x/net/context"
 )
 
 var _ = Describe("MessageEntryHandlerDispatcher", func() {
 	var (
 		messageHandlerCalls        int
 		deliveryHandlerCalls       int
+		readHandlerCalls           int
 		postbackHandlerCalls       int
 		authenticationHandlerCalls int
 	)
@@ -25,6 +29,11 @@ var _ = Describe("MessageEntryHandlerDispatcher", func() {
 		return nil
 	}
 
+	readHandler := func(entry *MessagingEntry) error {
+		readHandlerCalls++
+		return nil
+	}
+
 	postbackHandler := func(entry *MessagingEntry) error {
 		postbackHandlerCalls++
 		return nil
@@ -38,6 +47,7 @@ var _ = Describe("MessageEntryHandlerDispatcher", func() {
 	BeforeEach(func() {
 		messageHandlerCalls = 0
 		deliveryHandlerCalls = 0
+		readHandlerCalls = 0
 		postbackHandlerCalls = 0
 		authenticationHandlerCalls = 0
 	})
@@ -62,6 +72,16 @@ var _ = Describe("MessageEntryHandlerDispatcher", func() {
 		Expect(deliveryHandlerCalls).To(Equal(1))
 	})
 
+	It("should dispatch read callbacks to the read handler", func() {
+		dispatcher := &CallbackDispatcher{
+			ReadHandler: readHandler,
+		}
+
+		dispatcher.Dispatch(createReadCallback())
+
+		Expect(readHandlerCalls).To(Equal(1))
+	})
+
 	It("should dispatch postback callbacks to the postback handler", func() {
 		dispatcher := &CallbackDispatcher{
 			PostbackHandler: postbackHandler,
@@ -87,14 +107,111 @@ var _ = Describe("MessageEntryHandlerDispatcher", func() {
 
 		dispatcher.Dispatch(createMessageCallback())
 		dispatcher.Dispatch(createDeliveryCallback())
+		dispatcher.Dispatch(createReadCallback())
 		dispatcher.Dispatch(createPostbackCallback())
 		dispatcher.Dispatch(createAuthenticationCallback())
 
 		Expect(messageHandlerCalls).To(Equal(0))
 		Expect(deliveryHandlerCalls).To(Equal(0))
+		Expect(readHandlerCalls).To(Equal(0))
 		Expect(postbackHandlerCalls).To(Equal(0))
 		Expect(authenticationHandlerCalls).To(Equal(0))
 	})
+
+	Describe("Context Handlers", func() {
+		type contextKey string
+
+		It("should pass the context and page id to the context handler", func() {
+			var (
+				receivedValue  interface{}
+				receivedPageId string
+			)
+
+			dispatcher := &CallbackDispatcher{
+				PostbackContextHandler: func(ctx context.Context, pageId string, entry *MessagingEntry) error {
+					receivedValue = ctx.Value(contextKey("trace"))
+					receivedPageId = pageId
+					return nil
+				},
+			}
+
+			ctx := context.WithValue(context.Background(), contextKey("trace"), "TRACE_ID")
+			dispatcher.DispatchWithContext(ctx, createPostbackCallback())
+
+			Expect(receivedValue).To(Equal("TRACE_ID"))
+			Expect(receivedPageId).To(Equal("123"))
+		})
+
+		It("should prefer the context handler when both handlers are set", func() {
+			contextHandlerCalls := 0
+
+			dispatcher := &CallbackDispatcher{
+				MessageHandler: messageHandler,
+				MessageContextHandler: func(ctx context.Context, pageId string, entry *MessagingEntry) error {
+					contextHandlerCalls++
+					return nil
+				},
+			}
+
+			dispatcher.Dispatch(createMessageCallback())
+
+			Expect(contextHandlerCalls).To(Equal(1))
+			Expect(messageHandlerCalls).To(Equal(0))
+		})
+	})
+
+	Describe("Handler Errors", func() {
+		handlerErr := errors.New("handler failed")
+
+		failingHandler := func(entry *MessagingEntry) error {
+			messageHandlerCalls++
+			return handlerErr
+		}
+
+		createTwoMessageCallback := func() *Callback {
+			cb := createMessageCallback()
+			cb.Entries = append(cb.Entries, createMessageCallback().Entries[0])
+			return cb
+		}
+
+		It("should return nil when no handler returns an error", func() {
+			dispatcher := &CallbackDispatcher{
+				MessageHandler: messageHandler,
+			}
+
+			Expect(dispatcher.Dispatch(createMessageCallback())).To(Succeed())
+		})
+
+		It("should continue dispatching and aggregate errors by default", func() {
+			dispatcher := &CallbackDispatcher{
+				MessageHandler: failingHandler,
+			}
+
+			cb := createTwoMessageCallback()
+			err := dispatcher.Dispatch(cb)
+
+			Expect(messageHandlerCalls).To(Equal(2))
+
+			dispatchErr, ok := err.(*DispatchError)
+			Expect(ok).To(BeTrue())
+			Expect(dispatchErr.Errors).To(HaveLen(2))
+			Expect(dispatchErr.Errors[0].Entry).To(Equal(cb.Entries[0]))
+			Expect(dispatchErr.Errors[0].MessagingEntry).To(Equal(cb.Entries[0].Messaging[0]))
+			Expect(errors.Is(err, handlerErr)).To(BeTrue())
+		})
+
+		It("should stop at the first error when configured to", func() {
+			dispatcher := &CallbackDispatcher{
+				MessageHandler: failingHandler,
+				ErrorPolicy:    StopOnFirstError,
+			}
+
+			err := dispatcher.Dispatch(createTwoMessageCallback())
+
+			Expect(messageHandlerCalls).To(Equal(1))
+			Expect(err.(*DispatchError).Errors).To(HaveLen(1))
+		})
+	})
 })
 
 func createMessageCallback() *Callback {
@@ -134,6 +251,24 @@ func createDeliveryCallback() *Callback {
 	return cb
 }
 
+func createReadCallback() *Callback {
+	cb := createCallback()
+
+	cb.Entries[0].Messaging = []*MessagingEntry{
+		&MessagingEntry{
+			Sender:    Principal{Id: "456"},
+			Recipient: Principal{Id: "765"},
+			Timestamp: 876,
+			Read: &Read{
+				Watermark: 234,
+				Sequence:  88,
+			},
+		},
+	}
+
+	return cb
+}
+
 func createPostbackCallback() *Callback {
 	cb := createCallback()
 
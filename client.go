@@ -2,45 +2,77 @@ package fbmessenger
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"golang.org/x/net/context"
+	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"net/url"
+	"strings"
 )
 
-const apiURL = "https://graph.facebook.com/v2.6"
+const graphURL = "https://graph.facebook.com"
 
-type httpDoer interface {
-	Do(req *http.Request) (*http.Response, error)
-}
+// DefaultAPIVersion is the version of the Graph API used when a Client has no APIVersion.
+const DefaultAPIVersion = "v21.0"
 
 /*
 Client is used to send messages and get user profiles. Use the empty value in most cases.
 The URL field can be overridden to allow for writing integration tests that use a different
-endpoint (not Facebook).
+endpoint (not Facebook). Otherwise, APIVersion (e.g. "v21.0") selects the version of the
+Graph API, and defaults to DefaultAPIVersion.
+
+Set HTTPClient to control the transport, proxies and timeouts used to make requests.
+
+	client := fbmessenger.Client{
+		APIVersion: "v21.0",
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+Set AppSecret for apps that require an appsecret_proof with every request. It is computed
+from the page access token and added to each request.
+
+Set ReturnSendErrors to have the Send methods return a SendError set on a response as
+the error value too. Set RetryPolicy to retry requests that fail with a transient error,
+and RateLimiter to limit how fast messages are sent for each page.
+
+Set ValidateRequests to have Send return a *ValidationError, without making a request, when
+the request breaks a rule Facebook enforces (see SendRequest.Validate).
 */
 type Client struct {
-	URL      string
-	httpDoer httpDoer
+	URL              string
+	APIVersion       string
+	HTTPClient       *http.Client
+	AppSecret        string
+	ReturnSendErrors bool
+	ValidateRequests bool
+	RetryPolicy      *RetryPolicy
+	RateLimiter      *RateLimiter
 }
 
 /*
-Send POSTs a request to and returns a response from the Send API. A response from
-Facebook indicating an error does not return an error. Be sure to check for errors
-in sending, and errors in the response from Facebook.
+Send POSTs a request to and returns a response from the Send API. An error is returned
+if the request could not be sent, or if Facebook responded with a status code other
+than 2xx. In the latter case the error is an *APIError wrapping the *SendError returned
+from Facebook, which can be checked with helpers like IsRateLimited and IsUserBlocked.
 
 	response, err := client.Send(request, "YOUR_PAGE_ACCESS_TOKEN")
-	if err != nil {
-		//Got an error. Request never got to Facebook.
-	} else if response.Error != nil {
-		//Request got to Facebook. Facebook returned an error.
+	if fbmessenger.IsUserBlocked(err) {
+		//Request got to Facebook. Stop sending to this user.
+	} else if err != nil {
+		//Got an error.
 	} else {
 		//Hooray!
 	}
 
+A response with a 2xx status code can still carry a SendError. Set ReturnSendErrors to
+have it returned as the error value too.
 */
 func (c *Client) Send(sendRequest *SendRequest, pageAccessToken string) (*SendResponse, error) {
 	return c.SendWithContext(context.Background(), sendRequest, pageAccessToken)
@@ -48,21 +80,93 @@ func (c *Client) Send(sendRequest *SendRequest, pageAccessToken string) (*SendRe
 
 // SendWithContext is like Send but allows you to timeout or cancel the request using context.Context.
 func (c *Client) SendWithContext(ctx context.Context, sendRequest *SendRequest, pageAccessToken string) (*SendResponse, error) {
-	var req *http.Request
-	var err error
+	if c.ValidateRequests {
+		err := sendRequest.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if isDataMessage(sendRequest) {
-		req, err = c.newFormDataRequest(sendRequest, pageAccessToken)
-	} else {
-		req, err = c.newJSONRequest(sendRequest, pageAccessToken)
+	upload, isUpload := newFileUpload(sendRequest.Message.Attachment)
+
+	return c.send(ctx, pageAccessToken, upload, func() (*http.Request, error) {
+		if isUpload {
+			return c.newSendFormDataRequest(sendRequest, upload, pageAccessToken)
+		}
+
+		return c.newJSONRequest("POST", "/me/messages", sendRequest, pageAccessToken)
+	})
+}
+
+/*
+SendAll sends each request in order, such as the chunks of a long text message created with
+SplitTextMessage. It stops at the first error, and returns the responses for the requests
+sent before it along with the error.
+*/
+func (c *Client) SendAll(sendRequests []*SendRequest, pageAccessToken string) ([]*SendResponse, error) {
+	return c.SendAllWithContext(context.Background(), sendRequests, pageAccessToken)
+}
+
+// SendAllWithContext is like SendAll but allows you to timeout or cancel the requests using context.Context.
+func (c *Client) SendAllWithContext(ctx context.Context, sendRequests []*SendRequest, pageAccessToken string) ([]*SendResponse, error) {
+	responses := make([]*SendResponse, 0, len(sendRequests))
+
+	for _, sendRequest := range sendRequests {
+		response, err := c.SendWithContext(ctx, sendRequest, pageAccessToken)
+		if err != nil {
+			return responses, err
+		}
+
+		responses = append(responses, response)
 	}
 
-	if err != nil {
-		return nil, err
+	return responses, nil
+}
+
+// SendSenderAction POSTs a request to the Send API to turn typing indicators on or off,
+// or to mark the last message from the user as seen.
+func (c *Client) SendSenderAction(senderActionRequest *SenderActionRequest, pageAccessToken string) (*SendResponse, error) {
+	return c.SendSenderActionWithContext(context.Background(), senderActionRequest, pageAccessToken)
+}
+
+// SendSenderActionWithContext is like SendSenderAction but allows you to timeout or cancel the request using context.Context.
+func (c *Client) SendSenderActionWithContext(ctx context.Context, senderActionRequest *SenderActionRequest, pageAccessToken string) (*SendResponse, error) {
+	return c.send(ctx, pageAccessToken, nil, func() (*http.Request, error) {
+		return c.newJSONRequest("POST", "/me/messages", senderActionRequest, pageAccessToken)
+	})
+}
+
+/*
+UploadAttachment uploads an attachment, by URL or by uploading its bytes, so that it can be
+reused in messages to many users without uploading it again. The returned AttachmentId is
+used with AttachmentIdMessage.
+
+	response, err := client.UploadAttachment(fbmessenger.ImageMessage("https://example.com/logo.png").Message.Attachment, "YOUR_PAGE_ACCESS_TOKEN")
+	request := fbmessenger.AttachmentIdMessage("image", response.AttachmentId).To("USER_ID")
+
+See https://developers.facebook.com/docs/messenger-platform/reference/attachment-upload-api
+*/
+func (c *Client) UploadAttachment(attachment *Attachment, pageAccessToken string) (*UploadResponse, error) {
+	return c.UploadAttachmentWithContext(context.Background(), attachment, pageAccessToken)
+}
+
+// UploadAttachmentWithContext is like UploadAttachment but allows you to timeout or cancel the request using context.Context.
+func (c *Client) UploadAttachmentWithContext(ctx context.Context, attachment *Attachment, pageAccessToken string) (*UploadResponse, error) {
+	message := Message{Attachment: reusableAttachment(attachment)}
+	upload, isUpload := newFileUpload(message.Attachment)
+
+	newRequest := func() (*http.Request, error) {
+		if isUpload {
+			return c.newFormDataRequest("/me/message_attachments", upload, pageAccessToken, func(w *multipart.Writer) error {
+				return writeFormField(w, "message", message)
+			})
+		}
+
+		return c.newJSONRequest("POST", "/me/message_attachments", &uploadRequest{Message: message}, pageAccessToken)
 	}
 
-	response := &SendResponse{}
-	err = c.doRequest(ctx, req, response)
+	response := &UploadResponse{}
+	err := c.doRequest(ctx, upload, newRequest, response)
 	if err != nil {
 		return nil, err
 	}
@@ -70,23 +174,47 @@ func (c *Client) SendWithContext(ctx context.Context, sendRequest *SendRequest,
 	return response, nil
 }
 
-func isDataMessage(sendRequest *SendRequest) bool {
-	if sendRequest.Message.Attachment == nil {
-		return false
+type uploadRequest struct {
+	Message Message `json:"message"`
+}
+
+// send makes a request to the Send API, applying the RateLimiter to each attempt.
+func (c *Client) send(ctx context.Context, pageAccessToken string, upload *fileUpload, newRequest func() (*http.Request, error)) (*SendResponse, error) {
+	limitedRequest := func() (*http.Request, error) {
+		if c.RateLimiter != nil {
+			err := c.RateLimiter.Wait(ctx, pageAccessToken)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return newRequest()
+	}
+
+	response := &SendResponse{}
+	err := c.doRequest(ctx, upload, limitedRequest, response)
+	if c.RateLimiter != nil && IsRateLimited(err) {
+		c.RateLimiter.Throttled(pageAccessToken, err)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	_, ok := sendRequest.Message.Attachment.Payload.(DataPayload)
+	if c.ReturnSendErrors && response.Error != nil {
+		return response, response.Error
+	}
 
-	return ok
+	return response, nil
 }
 
-func (c *Client) newJSONRequest(sendRequest *SendRequest, pageAccessToken string) (*http.Request, error) {
-	requestBytes, err := json.Marshal(sendRequest)
+func (c *Client) newJSONRequest(method, path string, body interface{}, pageAccessToken string) (*http.Request, error) {
+	requestBytes, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.buildURL("/me/messages?access_token="+pageAccessToken), bytes.NewBuffer(requestBytes))
+	req, err := http.NewRequest(method, c.buildURL(path, pageAccessToken, nil), bytes.NewBuffer(requestBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -96,54 +224,152 @@ func (c *Client) newJSONRequest(sendRequest *SendRequest, pageAccessToken string
 	return req, nil
 }
 
-func (c *Client) newFormDataRequest(sendRequest *SendRequest, pageAccessToken string) (*http.Request, error) {
-	payload, _ := sendRequest.Message.Attachment.Payload.(DataPayload)
+func (c *Client) newSendFormDataRequest(sendRequest *SendRequest, upload *fileUpload, pageAccessToken string) (*http.Request, error) {
+	return c.newFormDataRequest("/me/messages", upload, pageAccessToken, func(w *multipart.Writer) error {
+		if sendRequest.MessagingType != "" {
+			err := w.WriteField("messaging_type", sendRequest.MessagingType)
+			if err != nil {
+				return err
+			}
+		}
 
-	var reqBuffer bytes.Buffer
-	w := multipart.NewWriter(&reqBuffer)
+		err := writeFormField(w, "recipient", sendRequest.Recipient)
+		if err != nil {
+			return err
+		}
+
+		err = writeFormField(w, "message", sendRequest.Message)
+		if err != nil {
+			return err
+		}
+
+		if sendRequest.NotificationType != "" {
+			err = w.WriteField("notification_type", sendRequest.NotificationType)
+			if err != nil {
+				return err
+			}
+		}
+
+		if sendRequest.Tag != "" {
+			err = w.WriteField("tag", string(sendRequest.Tag))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
 
-	err := writeFormField(w, "recipient", sendRequest.Recipient)
+// newFormDataRequest builds a multipart request with the fields written by writeFields,
+// followed by the file to upload. Files from a ReaderPayload are streamed through a pipe
+// rather than buffered.
+func (c *Client) newFormDataRequest(path string, upload *fileUpload, pageAccessToken string, writeFields func(w *multipart.Writer) error) (*http.Request, error) {
+	file, err := upload.open()
 	if err != nil {
 		return nil, err
 	}
 
-	err = writeFormField(w, "message", sendRequest.Message)
-	if err != nil {
-		return nil, err
+	if !upload.streaming {
+		var reqBuffer bytes.Buffer
+		w := multipart.NewWriter(&reqBuffer)
+
+		err = writeMultipart(w, writeFields, upload, file)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest("POST", c.buildURL(path, pageAccessToken, nil), &reqBuffer)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", w.FormDataContentType())
+
+		return req, nil
 	}
 
-	if sendRequest.NotificationType != "" {
-		err = w.WriteField("notification_type", sendRequest.NotificationType)
+	pipeReader, pipeWriter := io.Pipe()
+	w := multipart.NewWriter(pipeWriter)
+
+	var contentLength int64 = -1
+	if upload.size >= 0 {
+		contentLength, err = multipartLength(w.Boundary(), writeFields, upload)
 		if err != nil {
 			return nil, err
 		}
+
+		file = io.LimitReader(file, upload.size)
+	}
+
+	req, err := http.NewRequest("POST", c.buildURL(path, pageAccessToken, nil), pipeReader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ContentLength = contentLength
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	writing := make(chan struct{})
+	upload.writing = writing
+
+	go func() {
+		defer close(writing)
+		pipeWriter.CloseWithError(writeMultipart(w, writeFields, upload, file))
+	}()
+
+	return req, nil
+}
+
+func writeMultipart(w *multipart.Writer, writeFields func(w *multipart.Writer) error, upload *fileUpload, file io.Reader) error {
+	err := writeFields(w)
+	if err != nil {
+		return err
 	}
 
 	header := make(textproto.MIMEHeader)
-	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, "filedata", payload.FileName))
-	header.Set("Content-Type", payload.ContentType)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, "filedata", upload.fileName))
+	header.Set("Content-Type", upload.contentType)
 
 	fileWriter, err := w.CreatePart(header)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	_, err = fileWriter.Write(payload.Data)
+	_, err = io.Copy(fileWriter, file)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	w.Close()
+	return w.Close()
+}
 
-	req, err := http.NewRequest("POST", c.buildURL("/me/messages?access_token="+pageAccessToken), &reqBuffer)
-	//req, err := http.NewRequest("POST", "http://httpbin.org/post", &reqBuffer)
+// multipartLength computes the length of a multipart body without reading the file, by
+// writing the body with an empty file and adding the size of the file.
+func multipartLength(boundary string, writeFields func(w *multipart.Writer) error, upload *fileUpload) (int64, error) {
+	counter := &countingWriter{}
+	w := multipart.NewWriter(counter)
+
+	err := w.SetBoundary(boundary)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	req.Header.Set("Content-Type", w.FormDataContentType())
+	err = writeMultipart(w, writeFields, upload, bytes.NewReader(nil))
+	if err != nil {
+		return 0, err
+	}
 
-	return req, nil
+	return counter.n + upload.size, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.n += int64(len(p))
+	return len(p), nil
 }
 
 func writeFormField(w *multipart.Writer, fieldName string, value interface{}) error {
@@ -162,15 +388,17 @@ func (c *Client) GetUserProfile(userId, pageAccessToken string) (*UserProfile, e
 
 // GetUserProfileWithContext is like GetUserProfile but allows you to timeout or cancel the request using context.Context.
 func (c *Client) GetUserProfileWithContext(ctx context.Context, userId, pageAccessToken string) (*UserProfile, error) {
-	url := c.buildURL(fmt.Sprintf("/%v?fields=first_name,last_name,profile_pic,locale,timezone,gender&access_token=%v", userId, pageAccessToken))
+	query := url.Values{}
+	query.Set("fields", "first_name,last_name,profile_pic,locale,timezone,gender")
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+	profileURL := c.buildURL("/"+userId, pageAccessToken, query)
+
+	newRequest := func() (*http.Request, error) {
+		return http.NewRequest("GET", profileURL, nil)
 	}
 
 	userProfile := &UserProfile{}
-	err = c.doRequest(ctx, req, userProfile)
+	err := c.doRequest(ctx, nil, newRequest, userProfile)
 	if err != nil {
 		return nil, err
 	}
@@ -178,24 +406,163 @@ func (c *Client) GetUserProfileWithContext(ctx context.Context, userId, pageAcce
 	return userProfile, nil
 }
 
-func (c *Client) buildURL(path string) string {
-	url := c.URL
-	if url == "" {
-		url = apiURL
+/*
+GetMessengerProfile GETs the requested fields of the Messenger Profile of the page.
+
+	profile, err := client.GetMessengerProfile([]fbmessenger.MessengerProfileField{fbmessenger.GreetingField}, "YOUR_PAGE_ACCESS_TOKEN")
+
+See https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api
+*/
+func (c *Client) GetMessengerProfile(fields []MessengerProfileField, pageAccessToken string) (*MessengerProfile, error) {
+	return c.GetMessengerProfileWithContext(context.Background(), fields, pageAccessToken)
+}
+
+// GetMessengerProfileWithContext is like GetMessengerProfile but allows you to timeout or cancel the request using context.Context.
+func (c *Client) GetMessengerProfileWithContext(ctx context.Context, fields []MessengerProfileField, pageAccessToken string) (*MessengerProfile, error) {
+	fieldNames := make([]string, len(fields))
+	for i, field := range fields {
+		fieldNames[i] = string(field)
+	}
+
+	query := url.Values{}
+	query.Set("fields", strings.Join(fieldNames, ","))
+
+	profileURL := c.buildURL("/me/messenger_profile", pageAccessToken, query)
+
+	newRequest := func() (*http.Request, error) {
+		return http.NewRequest("GET", profileURL, nil)
 	}
 
-	return url + path
+	response := &messengerProfileResponse{}
+	err := c.doRequest(ctx, nil, newRequest, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Data) == 0 {
+		return &MessengerProfile{}, nil
+	}
+
+	return response.Data[0], nil
 }
 
-func (c *Client) doRequest(ctx context.Context, req *http.Request, responseStruct interface{}) error {
-	req.Cancel = ctx.Done()
+/*
+SetMessengerProfile POSTs the fields set in profile to the Messenger Profile of the page.
+Fields that are not set are left unchanged.
+
+	profile := fbmessenger.NewMessengerProfile().
+		WithGetStarted("GET_STARTED_PAYLOAD").
+		WithGreeting(fbmessenger.GreetingText("Hello, {{user_first_name}}!"))
+
+	err := client.SetMessengerProfile(profile, "YOUR_PAGE_ACCESS_TOKEN")
+*/
+func (c *Client) SetMessengerProfile(profile *MessengerProfile, pageAccessToken string) error {
+	return c.SetMessengerProfileWithContext(context.Background(), profile, pageAccessToken)
+}
 
-	doer := c.httpDoer
-	if doer == nil {
-		doer = &http.Client{}
+// SetMessengerProfileWithContext is like SetMessengerProfile but allows you to timeout or cancel the request using context.Context.
+func (c *Client) SetMessengerProfileWithContext(ctx context.Context, profile *MessengerProfile, pageAccessToken string) error {
+	newRequest := func() (*http.Request, error) {
+		return c.newJSONRequest("POST", "/me/messenger_profile", profile, pageAccessToken)
 	}
 
-	resp, err := doer.Do(req)
+	return c.doRequest(ctx, nil, newRequest, &messengerProfileResult{})
+}
+
+// DeleteMessengerProfile DELETEs the fields from the Messenger Profile of the page.
+func (c *Client) DeleteMessengerProfile(fields []MessengerProfileField, pageAccessToken string) error {
+	return c.DeleteMessengerProfileWithContext(context.Background(), fields, pageAccessToken)
+}
+
+// DeleteMessengerProfileWithContext is like DeleteMessengerProfile but allows you to timeout or cancel the request using context.Context.
+func (c *Client) DeleteMessengerProfileWithContext(ctx context.Context, fields []MessengerProfileField, pageAccessToken string) error {
+	newRequest := func() (*http.Request, error) {
+		return c.newJSONRequest("DELETE", "/me/messenger_profile", &messengerProfileFields{Fields: fields}, pageAccessToken)
+	}
+
+	return c.doRequest(ctx, nil, newRequest, &messengerProfileResult{})
+}
+
+type messengerProfileResponse struct {
+	Data []*MessengerProfile `json:"data"`
+}
+
+type messengerProfileResult struct {
+	Result string `json:"result"`
+}
+
+type messengerProfileFields struct {
+	Fields []MessengerProfileField `json:"fields"`
+}
+
+// buildURL adds the page access token, and the appsecret_proof if the client has an
+// AppSecret, to the query string of the URL for path.
+func (c *Client) buildURL(path, pageAccessToken string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+
+	query.Set("access_token", pageAccessToken)
+	if c.AppSecret != "" {
+		query.Set("appsecret_proof", appSecretProof(pageAccessToken, c.AppSecret))
+	}
+
+	return c.baseURL() + path + "?" + query.Encode()
+}
+
+func (c *Client) baseURL() string {
+	if c.URL != "" {
+		return c.URL
+	}
+
+	version := c.APIVersion
+	if version == "" {
+		version = DefaultAPIVersion
+	}
+
+	return graphURL + "/" + version
+}
+
+// appSecretProof is the HMAC-SHA256 of the access token using the app secret.
+// See https://developers.facebook.com/docs/graph-api/securing-requests
+func appSecretProof(accessToken, appSecret string) string {
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write([]byte(accessToken))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// doRequest makes a request built by newRequest, retrying according to the RetryPolicy.
+// A new request is built for each attempt so that each has a complete body. Requests that
+// upload a file which cannot be read again are not retried, and do not wait before failing.
+func (c *Client) doRequest(ctx context.Context, upload *fileUpload, newRequest func() (*http.Request, error), responseStruct interface{}) error {
+	for attempt := 1; ; attempt++ {
+		err := c.doAttempt(ctx, newRequest, responseStruct)
+
+		if err == nil || attempt >= c.RetryPolicy.maxAttempts() || ctx.Err() != nil || !upload.canRetry() || !c.RetryPolicy.retryable(err) {
+			return err
+		}
+
+		if !c.RetryPolicy.wait(ctx, attempt) {
+			return err
+		}
+	}
+}
+
+func (c *Client) doAttempt(ctx context.Context, newRequest func() (*http.Request, error), responseStruct interface{}) error {
+	req, err := newRequest()
+	if err != nil {
+		return err
+	}
+
+	req = req.WithContext(ctx)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -207,6 +574,10 @@ func (c *Client) doRequest(ctx context.Context, req *http.Request, responseStruc
 		return err
 	}
 
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp, body)
+	}
+
 	err = json.Unmarshal(body, responseStruct)
 	if err != nil {
 		return err
@@ -7,12 +7,28 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
 
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"golang.org/x/net/context"
+	"io"
 	"io/ioutil"
 	"mime"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 var _ = Describe("Client", func() {
 	Describe("Send", func() {
 		const (
@@ -130,5 +146,653 @@ var _ = Describe("Client", func() {
 
 			Expect(mediaType).To(Equal("multipart/form-data"))
 		})
+
+		Describe("Messenger Profile", func() {
+			It("should GET the requested fields", func() {
+				responseBytes, err := ioutil.ReadFile("./sample-messenger-profile-data/get-response.json")
+				if err != nil {
+					Fail(fmt.Sprintf("Error reading response file: %v", err))
+				}
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/me/messenger_profile"),
+						ghttp.VerifyForm(map[string][]string{"fields": {"get_started,greeting"}}),
+						ghttp.RespondWith(200, responseBytes),
+					),
+				)
+
+				profile, err := client.GetMessengerProfile([]MessengerProfileField{GetStartedField, GreetingField}, pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(profile.GetStarted.Payload).To(Equal("GET_STARTED_PAYLOAD"))
+				Expect(profile.Greeting[0].Text).To(Equal("Hello, {{user_first_name}}!"))
+			})
+
+			It("should POST json when setting fields", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/me/messenger_profile"),
+						ghttp.VerifyJSON(`{"get_started":{"payload":"GET_STARTED_PAYLOAD"}}`),
+						ghttp.RespondWith(200, `{"result":"success"}`),
+					),
+				)
+
+				err := client.SetMessengerProfile(NewMessengerProfile().WithGetStarted("GET_STARTED_PAYLOAD"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+			})
+
+			It("should DELETE fields", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", "/me/messenger_profile"),
+						ghttp.VerifyJSON(`{"fields":["persistent_menu","whitelisted_domains"]}`),
+						ghttp.RespondWith(200, `{"result":"success"}`),
+					),
+				)
+
+				err := client.DeleteMessengerProfile([]MessengerProfileField{PersistentMenuField, WhitelistedDomainsField}, pageAccessToken)
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		It("should POST form data with the file name when sending a file attached by uploading the file", func() {
+			var (
+				fileName    string
+				contentType string
+				fileData    []byte
+			)
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/me/messages"),
+					func(w http.ResponseWriter, req *http.Request) {
+						file, header, err := req.FormFile("filedata")
+						if err != nil {
+							Fail(fmt.Sprintf("Error reading file from form: %v", err))
+						}
+						defer file.Close()
+
+						fileName = header.Filename
+						contentType = header.Header.Get("Content-Type")
+						fileData, _ = ioutil.ReadAll(file)
+					},
+					ghttp.RespondWithJSONEncoded(200, &SendResponse{
+						RecipientId: userId,
+						MessageId:   "mid.12345",
+					}),
+				),
+			)
+
+			request := FileDataMessage([]byte("%PDF-1.4"), "application/pdf", "receipt.pdf").To("USER_ID")
+			_, err := client.Send(request, pageAccessToken)
+
+			Expect(err).To(BeNil())
+			Expect(fileName).To(Equal("receipt.pdf"))
+			Expect(contentType).To(Equal("application/pdf"))
+			Expect(string(fileData)).To(Equal("%PDF-1.4"))
+		})
+
+		Describe("Streaming Uploads", func() {
+			var (
+				received        []string
+				contentLengths  []int64
+				streamedContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+			)
+
+			recordFile := func(w http.ResponseWriter, req *http.Request) {
+				contentLengths = append(contentLengths, req.ContentLength)
+
+				file, _, err := req.FormFile("filedata")
+				if err != nil {
+					Fail(fmt.Sprintf("Error reading file from form: %v", err))
+				}
+				defer file.Close()
+
+				fileBytes, _ := ioutil.ReadAll(file)
+				received = append(received, string(fileBytes))
+			}
+
+			respondWithSuccess := ghttp.RespondWithJSONEncoded(200, &SendResponse{
+				RecipientId: userId,
+				MessageId:   "mid.12345",
+			})
+
+			BeforeEach(func() {
+				received = nil
+				contentLengths = nil
+			})
+
+			It("should stream the file with a content length when the size is known", func() {
+				server.AppendHandlers(ghttp.CombineHandlers(recordFile, respondWithSuccess))
+
+				reader := strings.NewReader(streamedContent)
+				request := ReaderMessage("file", reader, int64(len(streamedContent)), "text/plain", "content.txt").To("USER_ID")
+
+				_, err := client.Send(request, pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(received).To(Equal([]string{streamedContent}))
+				Expect(contentLengths[0]).To(BeNumerically(">", len(streamedContent)))
+			})
+
+			It("should stream the file when the size is unknown", func() {
+				server.AppendHandlers(ghttp.CombineHandlers(recordFile, respondWithSuccess))
+
+				reader := struct{ io.Reader }{strings.NewReader(streamedContent)}
+				request := ReaderMessage("file", reader, 0, "text/plain", "content.txt").To("USER_ID")
+
+				_, err := client.Send(request, pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(received).To(Equal([]string{streamedContent}))
+				Expect(contentLengths[0]).To(Equal(int64(-1)))
+			})
+
+			Describe("Retries", func() {
+				BeforeEach(func() {
+					client.RetryPolicy = &RetryPolicy{
+						MaxAttempts:    2,
+						InitialBackoff: time.Millisecond,
+					}
+				})
+
+				It("should rewind a seekable reader to retry", func() {
+					server.AppendHandlers(
+						ghttp.CombineHandlers(recordFile, ghttp.RespondWith(503, "Service Unavailable")),
+						ghttp.CombineHandlers(recordFile, respondWithSuccess),
+					)
+
+					reader := strings.NewReader("HEADER" + streamedContent)
+					reader.Seek(int64(len("HEADER")), io.SeekStart)
+					request := ReaderMessage("file", reader, 0, "text/plain", "content.txt").To("USER_ID")
+
+					_, err := client.Send(request, pageAccessToken)
+
+					Expect(err).To(BeNil())
+					Expect(received).To(Equal([]string{streamedContent, streamedContent}))
+				})
+
+				It("should return the error without retrying when the reader is not seekable", func() {
+					client.RetryPolicy.InitialBackoff = time.Minute
+
+					server.AppendHandlers(
+						ghttp.CombineHandlers(recordFile, ghttp.RespondWith(503, "Service Unavailable")),
+					)
+
+					reader := struct{ io.Reader }{strings.NewReader(streamedContent)}
+					request := ReaderMessage("file", reader, 0, "text/plain", "content.txt").To("USER_ID")
+
+					start := time.Now()
+					_, err := client.Send(request, pageAccessToken)
+
+					Expect(err.(*APIError).StatusCode).To(Equal(503))
+					Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+					Expect(server.ReceivedRequests()).To(HaveLen(1))
+				})
+			})
+		})
+
+		Describe("Attachment Upload", func() {
+			It("should POST json when uploading an attachment by URL", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/me/message_attachments"),
+						ghttp.VerifyJSON(`{"message":{"attachment":{"type":"image","payload":{"url":"http://someurl.com/pic.jpg","is_reusable":true}}}}`),
+						ghttp.RespondWith(200, `{"attachment_id":"1857777774821032"}`),
+					),
+				)
+
+				attachment := ImageMessage("http://someurl.com/pic.jpg").Message.Attachment
+				response, err := client.UploadAttachment(attachment, pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(response.AttachmentId).To(Equal("1857777774821032"))
+			})
+
+			It("should POST form data when uploading the bytes of an attachment", func() {
+				var message string
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/me/message_attachments"),
+						func(w http.ResponseWriter, req *http.Request) {
+							message = req.FormValue("message")
+						},
+						ghttp.RespondWith(200, `{"attachment_id":"1857777774821032"}`),
+					),
+				)
+
+				attachment := FileDataMessage([]byte("%PDF-1.4"), "application/pdf", "receipt.pdf").Message.Attachment
+				response, err := client.UploadAttachment(attachment, pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(response.AttachmentId).To(Equal("1857777774821032"))
+				Expect(message).To(MatchJSON(`{"attachment":{"type":"file","payload":{"is_reusable":true}}}`))
+			})
+		})
+
+		Describe("Errors from Facebook", func() {
+			sendError := &SendError{
+				Message:      "(#551) This person isn't available right now.",
+				Type:         "OAuthException",
+				Code:         551,
+				ErrorSubcode: 1545041,
+				FBTraceId:    "D2kxCybrKVw",
+			}
+
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(200, &SendResponse{
+						Error: sendError,
+					}),
+				)
+			})
+
+			It("should set the error on the response", func() {
+				response, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(response.Error).To(Equal(sendError))
+			})
+
+			It("should return the error when configured to", func() {
+				client.ReturnSendErrors = true
+
+				response, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(response.Error).To(Equal(sendError))
+				Expect(err).To(Equal(sendError))
+				Expect(IsUserBlocked(err)).To(BeTrue())
+			})
+		})
+
+		Describe("Status Codes", func() {
+			It("should return an APIError wrapping the SendError when Facebook responds with an error", func() {
+				errorBytes, err := ioutil.ReadFile("./sample-send-api-data/error-response.json")
+				if err != nil {
+					Fail(fmt.Sprintf("Error reading error response file: %v", err))
+				}
+
+				server.AppendHandlers(ghttp.RespondWith(400, errorBytes))
+
+				response, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(response).To(BeNil())
+
+				apiErr, ok := err.(*APIError)
+				Expect(ok).To(BeTrue())
+				Expect(apiErr.StatusCode).To(Equal(400))
+				Expect(apiErr.FBTraceId).To(Equal("D2kxCybrKVw"))
+				Expect(apiErr.SendError.Code).To(Equal(100))
+
+				var sendErr *SendError
+				Expect(errors.As(err, &sendErr)).To(BeTrue())
+				Expect(sendErr.Message).To(Equal("Invalid parameter"))
+			})
+
+			It("should return an APIError with the start of the body when the response is not json", func() {
+				server.AppendHandlers(ghttp.RespondWith(502, "<html>Bad Gateway</html>"))
+
+				_, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				apiErr, ok := err.(*APIError)
+				Expect(ok).To(BeTrue())
+				Expect(apiErr.StatusCode).To(Equal(502))
+				Expect(apiErr.Body).To(Equal("<html>Bad Gateway</html>"))
+				Expect(apiErr.SendError).To(BeNil())
+				Expect(errors.Unwrap(err)).To(BeNil())
+			})
+		})
+
+		Describe("Sender Actions", func() {
+			var (
+				mutex         sync.Mutex
+				senderActions []string
+				typingOnSent  chan struct{}
+			)
+
+			recordedActions := func() []string {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				return append([]string(nil), senderActions...)
+			}
+
+			BeforeEach(func() {
+				senderActions = nil
+				typingOnSent = nil
+
+				server.RouteToHandler("POST", "/me/messages", func(w http.ResponseWriter, req *http.Request) {
+					senderActionRequest := &SenderActionRequest{}
+					json.NewDecoder(req.Body).Decode(senderActionRequest)
+
+					if senderActionRequest.SenderAction == "typing_on" && typingOnSent != nil {
+						select {
+						case <-typingOnSent:
+						case <-time.After(time.Second):
+						}
+					}
+
+					mutex.Lock()
+					senderActions = append(senderActions, senderActionRequest.SenderAction)
+					mutex.Unlock()
+
+					w.Write([]byte(`{"recipient_id":"USER_ID"}`))
+				})
+			})
+
+			It("should POST json when sending a sender action", func() {
+				response, err := client.SendSenderAction(MarkSeen().To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(response.RecipientId).To(Equal(userId))
+				Expect(senderActions).To(Equal([]string{"mark_seen"}))
+			})
+
+			It("should show typing while a function runs", func() {
+				handlerErr := errors.New("handler failed")
+
+				err := client.WhileTyping(context.Background(), "USER_ID", pageAccessToken, func(ctx context.Context) error {
+					Eventually(recordedActions).Should(Equal([]string{"typing_on"}))
+
+					return handlerErr
+				})
+
+				Expect(err).To(Equal(handlerErr))
+				Expect(senderActions).To(Equal([]string{"typing_on", "typing_off"}))
+			})
+
+			It("should run the function without waiting for typing to be turned on", func() {
+				typingOnSent = make(chan struct{})
+				var actionsWhenRun []string
+
+				client.WhileTyping(context.Background(), "USER_ID", pageAccessToken, func(ctx context.Context) error {
+					actionsWhenRun = recordedActions()
+					close(typingOnSent)
+
+					return nil
+				})
+
+				Expect(actionsWhenRun).To(BeEmpty())
+				Expect(senderActions).To(Equal([]string{"typing_on", "typing_off"}))
+			})
+
+			It("should turn typing off when the context is done", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+
+				client.WhileTyping(ctx, "USER_ID", pageAccessToken, func(ctx context.Context) error {
+					Eventually(recordedActions).Should(Equal([]string{"typing_on"}))
+					cancel()
+					<-ctx.Done()
+					return ctx.Err()
+				})
+
+				Expect(senderActions).To(Equal([]string{"typing_on", "typing_off"}))
+			})
+		})
+
+		Describe("Configuration", func() {
+			var requestedURLs []string
+
+			BeforeEach(func() {
+				requestedURLs = nil
+			})
+
+			recordingClient := func() *http.Client {
+				return &http.Client{
+					Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+						requestedURLs = append(requestedURLs, req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+
+						return &http.Response{
+							StatusCode: 200,
+							Header:     http.Header{},
+							Body:       ioutil.NopCloser(strings.NewReader(`{"recipient_id":"USER_ID","message_id":"mid.12345"}`)),
+						}, nil
+					}),
+				}
+			}
+
+			It("should send requests with the supplied http client to the default API version", func() {
+				client = &Client{HTTPClient: recordingClient()}
+
+				response, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(response.RecipientId).To(Equal(userId))
+				Expect(requestedURLs).To(Equal([]string{"https://graph.facebook.com/" + DefaultAPIVersion + "/me/messages"}))
+			})
+
+			It("should send requests to the configured API version", func() {
+				client = &Client{
+					APIVersion: "v3.0",
+					HTTPClient: recordingClient(),
+				}
+
+				_, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(requestedURLs).To(Equal([]string{"https://graph.facebook.com/v3.0/me/messages"}))
+			})
+
+			It("should pass the context through to the transport of the http client", func() {
+				type contextKey string
+
+				var (
+					receivedValue interface{}
+					hasDeadline   bool
+				)
+
+				client = &Client{
+					HTTPClient: &http.Client{
+						Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+							receivedValue = req.Context().Value(contextKey("trace"))
+							_, hasDeadline = req.Context().Deadline()
+
+							return recordingClient().Transport.RoundTrip(req)
+						}),
+					},
+				}
+
+				ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), contextKey("trace"), "TRACE_ID"), time.Minute)
+				defer cancel()
+
+				_, err := client.SendWithContext(ctx, TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(receivedValue).To(Equal("TRACE_ID"))
+				Expect(hasDeadline).To(BeTrue())
+			})
+		})
+
+		Describe("App Secret Proof", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(200, &SendResponse{
+						RecipientId: userId,
+						MessageId:   "mid.12345",
+					}),
+				)
+			})
+
+			It("should add the appsecret_proof when the client has an app secret", func() {
+				client.AppSecret = "APP_SECRET"
+
+				_, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+				Expect(err).To(BeNil())
+
+				mac := hmac.New(sha256.New, []byte("APP_SECRET"))
+				mac.Write([]byte(pageAccessToken))
+
+				query := server.ReceivedRequests()[0].URL.Query()
+				Expect(query.Get("access_token")).To(Equal(pageAccessToken))
+				Expect(query.Get("appsecret_proof")).To(Equal(hex.EncodeToString(mac.Sum(nil))))
+			})
+
+			It("should not add the appsecret_proof when the client has no app secret", func() {
+				_, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+				Expect(err).To(BeNil())
+
+				query := server.ReceivedRequests()[0].URL.Query()
+				Expect(query.Get("access_token")).To(Equal(pageAccessToken))
+				Expect(query).ToNot(HaveKey("appsecret_proof"))
+			})
+		})
+
+		Describe("Sequences", func() {
+			It("should send requests in order and stop at the first error", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyJSON(`{"recipient":{"id":"USER_ID"},"message":{"text":"One."}}`),
+						ghttp.RespondWithJSONEncoded(200, &SendResponse{RecipientId: userId, MessageId: "mid.1"}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyJSON(`{"recipient":{"id":"USER_ID"},"message":{"text":"Two."}}`),
+						ghttp.RespondWithJSONEncoded(400, map[string]interface{}{
+							"error": &SendError{Message: "Invalid parameter", Code: 100},
+						}),
+					),
+				)
+
+				requests := []*SendRequest{
+					TextMessage("One.").To("USER_ID"),
+					TextMessage("Two.").To("USER_ID"),
+					TextMessage("Three.").To("USER_ID"),
+				}
+				responses, err := client.SendAll(requests, pageAccessToken)
+
+				Expect(err).To(HaveOccurred())
+				Expect(responses).To(HaveLen(1))
+				Expect(responses[0].MessageId).To(Equal("mid.1"))
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+
+		Describe("Validation", func() {
+			It("should not send an invalid request when validating requests", func() {
+				client.ValidateRequests = true
+
+				_, err := client.Send(TextMessage("").To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+
+		Describe("Rate Limiting", func() {
+			It("should throttle the page when Facebook responds that it is rate limited", func() {
+				var throttledToken string
+
+				client.RateLimiter = NewRateLimiter(100, 10)
+				client.RateLimiter.OnThrottled = func(token string, err error) {
+					throttledToken = token
+				}
+
+				server.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(400, map[string]interface{}{
+						"error": &SendError{Message: "Calls to this api have exceeded the rate limit.", Code: 613},
+					}),
+				)
+
+				_, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(IsRateLimited(err)).To(BeTrue())
+				Expect(throttledToken).To(Equal(pageAccessToken))
+			})
+		})
+
+		Describe("Retries", func() {
+			BeforeEach(func() {
+				client.RetryPolicy = &RetryPolicy{
+					MaxAttempts:    3,
+					InitialBackoff: time.Millisecond,
+				}
+			})
+
+			It("should retry transient failures until the request succeeds", func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(503, "Service Unavailable"),
+					ghttp.RespondWithJSONEncoded(500, map[string]interface{}{
+						"error": &SendError{Message: "Service temporarily unavailable", Code: 2},
+					}),
+					ghttp.RespondWithJSONEncoded(200, &SendResponse{
+						RecipientId: userId,
+						MessageId:   "mid.12345",
+					}),
+				)
+
+				response, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(response.RecipientId).To(Equal(userId))
+				Expect(server.ReceivedRequests()).To(HaveLen(3))
+			})
+
+			It("should give up after the maximum number of attempts", func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(503, "Service Unavailable"),
+					ghttp.RespondWith(503, "Service Unavailable"),
+					ghttp.RespondWith(503, "Service Unavailable"),
+				)
+
+				_, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err.(*APIError).StatusCode).To(Equal(503))
+				Expect(server.ReceivedRequests()).To(HaveLen(3))
+			})
+
+			It("should not retry errors that are not transient", func() {
+				server.AppendHandlers(ghttp.RespondWith(400, "Bad Request"))
+
+				_, err := client.Send(TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err).ToNot(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+
+			It("should not wait for a retry past the deadline of the context", func() {
+				client.RetryPolicy.InitialBackoff = time.Minute
+				server.AppendHandlers(ghttp.RespondWith(503, "Service Unavailable"))
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				_, err := client.SendWithContext(ctx, TextMessage("Hello, world!").To("USER_ID"), pageAccessToken)
+
+				Expect(err).ToNot(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+
+			It("should send the complete form data on each attempt", func() {
+				var bodyLengths []int
+
+				recordBodyLength := func(w http.ResponseWriter, req *http.Request) {
+					body, _ := ioutil.ReadAll(req.Body)
+					bodyLengths = append(bodyLengths, len(body))
+				}
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(recordBodyLength, ghttp.RespondWith(503, "Service Unavailable")),
+					ghttp.CombineHandlers(recordBodyLength, ghttp.RespondWithJSONEncoded(200, &SendResponse{
+						RecipientId: userId,
+						MessageId:   "mid.12345",
+					})),
+				)
+
+				imageBytes, err := ioutil.ReadFile("./sample-send-api-data/fb-logo.png")
+				if err != nil {
+					Fail(fmt.Sprintf("Error reading image file: %v", err))
+				}
+
+				_, err = client.Send(ImageDataMessage(imageBytes, "image/png").To("USER_ID"), pageAccessToken)
+
+				Expect(err).To(BeNil())
+				Expect(bodyLengths).To(HaveLen(2))
+				Expect(bodyLengths[0]).To(BeNumerically(">", len(imageBytes)))
+				Expect(bodyLengths[1]).To(Equal(bodyLengths[0]))
+			})
+		})
 	})
 })
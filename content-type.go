@@ -0,0 +1,154 @@
+package fbmessenger
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// acceptedContentTypes lists the content types Messenger accepts for each type of attachment.
+// Any content type is accepted for "file" attachments.
+var acceptedContentTypes = map[string][]string{
+	"image": {"image/jpeg", "image/png", "image/gif"},
+	"audio": {"audio/mpeg", "audio/mp4", "audio/aac", "audio/wave", "audio/x-wav", "audio/ogg"},
+	"video": {"video/mp4", "video/quicktime", "video/webm"},
+}
+
+// preferredExtensions holds extensions for common content types, since the extensions
+// returned by mime.ExtensionsByType depend on the system.
+var preferredExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"audio/mpeg":      ".mp3",
+	"audio/mp4":       ".m4a",
+	"audio/aac":       ".aac",
+	"audio/wave":      ".wav",
+	"audio/x-wav":     ".wav",
+	"audio/ogg":       ".ogg",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+	"text/plain":      ".txt",
+}
+
+// UnsupportedContentTypeError is returned when Messenger does not accept the content type
+// of data for the type of attachment.
+type UnsupportedContentTypeError struct {
+	AttachmentType string
+	ContentType    string
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("content type %v is not supported for %v attachments", e.ContentType, e.AttachmentType)
+}
+
+/*
+DetectContentType sniffs the content type of data, without any parameters (e.g. "image/png").
+Besides the types recognized by http.DetectContentType, it recognizes QuickTime video, MPEG-4
+audio, AAC audio in ADTS frames and MP3 audio without an ID3 tag, and reports Ogg data as audio.
+*/
+func DetectContentType(data []byte) string {
+	if contentType, ok := sniffMediaType(data); ok {
+		return contentType
+	}
+
+	contentType := http.DetectContentType(data)
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+
+	return mediaType
+}
+
+// sniffMediaType recognizes audio and video formats accepted by Messenger that
+// http.DetectContentType does not tell apart.
+func sniffMediaType(data []byte) (string, bool) {
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		switch string(data[8:12]) {
+		case "qt  ":
+			return "video/quicktime", true
+		case "M4A ", "M4B ":
+			return "audio/mp4", true
+		}
+	}
+
+	// ADTS frames start with a 12 bit sync word followed by a layer of 0. MPEG audio frames
+	// without an ID3 tag start with an 11 bit sync word and have a layer other than 0.
+	if len(data) >= 2 && data[0] == 0xFF {
+		switch {
+		case data[1]&0xF6 == 0xF0:
+			return "audio/aac", true
+		case data[1]&0xE0 == 0xE0 && data[1]&0x06 != 0:
+			return "audio/mpeg", true
+		}
+	}
+
+	if bytes.HasPrefix(data, []byte("OggS\x00")) {
+		return "audio/ogg", true
+	}
+
+	return "", false
+}
+
+// FileExtension returns an extension, including the leading dot, for the content type, or
+// an empty string if none is known.
+func FileExtension(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+
+	if extension, ok := preferredExtensions[contentType]; ok {
+		return extension
+	}
+
+	extensions, err := mime.ExtensionsByType(contentType)
+	if err != nil || len(extensions) == 0 {
+		return ""
+	}
+
+	return extensions[0]
+}
+
+/*
+NewDataPayload creates a DataPayload for data to upload as an attachment of type
+attachmentType ("image", "audio", "video" or "file"). The content type is sniffed from
+data, and the file name is the attachment type with a matching extension, like "image.jpg".
+An *UnsupportedContentTypeError is returned if Messenger does not accept the content type
+for the type of attachment.
+*/
+func NewDataPayload(attachmentType string, data []byte) (DataPayload, error) {
+	contentType := DetectContentType(data)
+
+	if !isAcceptedContentType(attachmentType, contentType) {
+		return DataPayload{}, &UnsupportedContentTypeError{
+			AttachmentType: attachmentType,
+			ContentType:    contentType,
+		}
+	}
+
+	return DataPayload{
+		Data:        data,
+		ContentType: contentType,
+		FileName:    fileNameFor(attachmentType, contentType),
+	}, nil
+}
+
+func isAcceptedContentType(attachmentType, contentType string) bool {
+	if attachmentType == "file" {
+		return true
+	}
+
+	for _, accepted := range acceptedContentTypes[attachmentType] {
+		if contentType == accepted {
+			return true
+		}
+	}
+
+	return false
+}
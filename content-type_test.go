@@ -0,0 +1,110 @@
+package fbmessenger_test
+
+import (
+	. "github.com/ekyoung/fbmessenger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"fmt"
+	"io/ioutil"
+)
+
+var _ = Describe("Content Types", func() {
+	var pngBytes []byte
+
+	BeforeEach(func() {
+		var err error
+		pngBytes, err = ioutil.ReadFile("./sample-send-api-data/fb-logo.png")
+		if err != nil {
+			Fail(fmt.Sprintf("Error reading image file: %v", err))
+		}
+	})
+
+	It("should detect the content type of data without parameters", func() {
+		Expect(DetectContentType(pngBytes)).To(Equal("image/png"))
+		Expect(DetectContentType([]byte("Hello, world!"))).To(Equal("text/plain"))
+	})
+
+	It("should detect audio and video types not recognized by net/http", func() {
+		mov := append([]byte{0x00, 0x00, 0x00, 0x14}, []byte("ftypqt  \x00\x00\x02\x00qt  ")...)
+		m4a := append([]byte{0x00, 0x00, 0x00, 0x1C}, []byte("ftypM4A \x00\x00\x00\x00M4A mp42isom")...)
+		aac := []byte{0xFF, 0xF1, 0x50, 0x80, 0x02, 0x1F, 0xFC}
+		ogg := append([]byte("OggS\x00\x02"), make([]byte, 22)...)
+		mp3 := []byte{0xFF, 0xFB, 0x90, 0x64, 0x00, 0x00, 0x00}
+
+		Expect(DetectContentType(mov)).To(Equal("video/quicktime"))
+		Expect(DetectContentType(m4a)).To(Equal("audio/mp4"))
+		Expect(DetectContentType(aac)).To(Equal("audio/aac"))
+		Expect(DetectContentType(ogg)).To(Equal("audio/ogg"))
+		Expect(DetectContentType(mp3)).To(Equal("audio/mpeg"))
+
+		_, err := NewDataPayload("video", mov)
+		Expect(err).To(BeNil())
+
+		payload, err := NewDataPayload("audio", m4a)
+		Expect(err).To(BeNil())
+		Expect(payload.FileName).To(Equal("audio.m4a"))
+
+		payload, err = NewDataPayload("audio", mp3)
+		Expect(err).To(BeNil())
+		Expect(payload.FileName).To(Equal("audio.mp3"))
+
+		_, err = DataMessage("audio", mp3)
+		Expect(err).To(BeNil())
+	})
+
+	It("should return extensions for common content types", func() {
+		Expect(FileExtension("image/jpeg")).To(Equal(".jpg"))
+		Expect(FileExtension("application/pdf")).To(Equal(".pdf"))
+		Expect(FileExtension("text/plain; charset=utf-8")).To(Equal(".txt"))
+		Expect(FileExtension("application/x-unknown-type")).To(Equal(""))
+	})
+
+	Describe("NewDataPayload", func() {
+		It("should detect the content type and file name", func() {
+			payload, err := NewDataPayload("image", pngBytes)
+
+			Expect(err).To(BeNil())
+			Expect(payload.ContentType).To(Equal("image/png"))
+			Expect(payload.FileName).To(Equal("image.png"))
+		})
+
+		It("should reject content types not accepted for the type of attachment", func() {
+			_, err := NewDataPayload("video", pngBytes)
+
+			Expect(err).To(Equal(&UnsupportedContentTypeError{AttachmentType: "video", ContentType: "image/png"}))
+		})
+
+		It("should accept any content type for file attachments", func() {
+			payload, err := NewDataPayload("file", []byte("%PDF-1.4"))
+
+			Expect(err).To(BeNil())
+			Expect(payload.ContentType).To(Equal("application/pdf"))
+			Expect(payload.FileName).To(Equal("file.pdf"))
+		})
+	})
+
+	Describe("Data Messages", func() {
+		It("should name files using the extension for the content type", func() {
+			payload := AudioDataMessage([]byte{}, "audio/mpeg").Message.Attachment.Payload.(DataPayload)
+			Expect(payload.FileName).To(Equal("audio.mp3"))
+
+			payload = ImageDataMessage([]byte{}, "image/jpeg").Message.Attachment.Payload.(DataPayload)
+			Expect(payload.FileName).To(Equal("image.jpg"))
+		})
+	})
+
+	Describe("DataMessage", func() {
+		It("should let the file name be overridden", func() {
+			request, err := DataMessage("file", []byte("%PDF-1.4"))
+			Expect(err).To(BeNil())
+
+			request.WithFileName("receipt.pdf")
+
+			payload := request.Message.Attachment.Payload.(DataPayload)
+			Expect(payload.FileName).To(Equal("receipt.pdf"))
+			Expect(payload.ContentType).To(Equal("application/pdf"))
+		})
+	})
+})
@@ -37,6 +37,16 @@ CallbackDispatcher Usage
 		//Do stuff
 	}
 
+	// Or use type WebhookHandler as the http.Handler for your webhook endpoint. It answers
+	// Facebook's verification request and dispatches each callback it receives.
+
+	handler := &fbmessenger.WebhookHandler{
+		VerifyToken: "YOUR_VERIFY_TOKEN",
+		Dispatcher:  dispatcher,
+	}
+
+	http.Handle("/webhook", handler)
+
 Client Usage
 
 	// Create a `Client` to make requests to the messenger API.
@@ -48,13 +58,14 @@ Client Usage
 
 	request := fbmessenger.TextMessage("Hello, world!").To("USER_ID")
 
-	// Then send your request and handle errors in sending, and errors returned from Facebook.
+	// Then send your request and handle errors. Errors returned from Facebook can be
+	// checked with helpers like IsRateLimited and IsUserBlocked.
 
 	response, err := client.Send(request, "YOUR_PAGE_ACCESS_TOKEN")
-	if err != nil {
-		//Got an error. Request never got to Facebook.
-	} else if response.Error != nil {
-		//Request got to Facebook. Facebook returned an error.
+	if fbmessenger.IsUserBlocked(err) {
+		//Request got to Facebook. Stop sending to this user.
+	} else if err != nil {
+		//Got an error.
 	} else {
 		//Hooray!
 	}
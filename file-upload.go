@@ -0,0 +1,100 @@
+package fbmessenger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// errNotRewindable is returned when building a request for a retry would require reading
+// the file of a ReaderPayload again, but its Reader is not an io.Seeker.
+var errNotRewindable = errors.New("reader of payload cannot be rewound to retry request")
+
+// fileUpload is the file part of a multipart request. It is created once per call to the
+// client so that open can rewind a ReaderPayload for each attempt.
+type fileUpload struct {
+	contentType string
+	fileName    string
+	size        int64
+	streaming   bool
+	rewindable  bool
+	open        func() (io.Reader, error)
+
+	// writing is closed when the goroutine streaming the previous attempt is done with
+	// the file, so that it is not read and rewound at the same time.
+	writing chan struct{}
+}
+
+// newFileUpload returns a fileUpload if the payload of attachment is uploaded as a file.
+func newFileUpload(attachment *Attachment) (*fileUpload, bool) {
+	if attachment == nil {
+		return nil, false
+	}
+
+	switch payload := attachment.Payload.(type) {
+	case DataPayload:
+		return &fileUpload{
+			contentType: payload.ContentType,
+			fileName:    payload.FileName,
+			size:        int64(len(payload.Data)),
+			rewindable:  true,
+			open: func() (io.Reader, error) {
+				return bytes.NewReader(payload.Data), nil
+			},
+		}, true
+	case ReaderPayload:
+		return newReaderUpload(payload), true
+	}
+
+	return nil, false
+}
+
+func newReaderUpload(payload ReaderPayload) *fileUpload {
+	size := payload.Size
+	if size <= 0 {
+		size = -1
+	}
+
+	seeker, seekable := payload.Reader.(io.Seeker)
+
+	var start int64
+	var startErr error
+	if seekable {
+		start, startErr = seeker.Seek(0, io.SeekCurrent)
+	}
+
+	upload := &fileUpload{
+		contentType: payload.ContentType,
+		fileName:    payload.FileName,
+		size:        size,
+		streaming:   true,
+		rewindable:  seekable && startErr == nil,
+	}
+
+	upload.open = func() (io.Reader, error) {
+		if upload.writing == nil {
+			return payload.Reader, nil
+		}
+
+		<-upload.writing
+
+		if !upload.rewindable {
+			return nil, errNotRewindable
+		}
+
+		_, err := seeker.Seek(start, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+
+		return payload.Reader, nil
+	}
+
+	return upload
+}
+
+// canRetry returns false if the file cannot be read again for another attempt. It is safe
+// to call on a nil fileUpload, for requests that do not upload a file.
+func (u *fileUpload) canRetry() bool {
+	return u == nil || u.rewindable
+}
@@ -2,6 +2,9 @@ package fbmessenger
 
 import (
 	"encoding/json"
+	"io"
+	"net/mail"
+	"regexp"
 	"strings"
 )
 
@@ -25,12 +28,154 @@ an image attached using the URL of the image.
 See https://developers.facebook.com/docs/messenger-platform/send-api-reference/image-attachment
 */
 func ImageMessage(url string) *SendRequest {
+	return resourceMessage("image", url)
+}
+
+/*
+ImageDataMessage is a fluent helper method for creating a SendRequest containing a message
+with an image attached by uploading the bytes of the image.
+
+	imageBytes, _ := ioutil.ReadFile("./cool-pic.png")
+	request := ImageDataMessage(imageBytes, "image/png").To("USER_ID")
+
+To detect the content type of a file dynamically, use DataMessage instead. Converting to
+one of the image formats supported by Facebook is the responsibility of the user.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/image-attachment
+*/
+func ImageDataMessage(data []byte, contentType string) *SendRequest {
+	return dataMessage("image", data, contentType, fileNameFor("image", contentType))
+}
+
+/*
+AudioMessage is a fluent helper method for creating a SendRequest containing a message with
+an audio file attached using the URL of the file.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/audio-attachment
+*/
+func AudioMessage(url string) *SendRequest {
+	return resourceMessage("audio", url)
+}
+
+/*
+AudioDataMessage is a fluent helper method for creating a SendRequest containing a message
+with an audio file attached by uploading the bytes of the file.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/audio-attachment
+*/
+func AudioDataMessage(data []byte, contentType string) *SendRequest {
+	return dataMessage("audio", data, contentType, fileNameFor("audio", contentType))
+}
+
+/*
+VideoMessage is a fluent helper method for creating a SendRequest containing a message with
+a video attached using the URL of the video.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/video-attachment
+*/
+func VideoMessage(url string) *SendRequest {
+	return resourceMessage("video", url)
+}
+
+/*
+VideoDataMessage is a fluent helper method for creating a SendRequest containing a message
+with a video attached by uploading the bytes of the video.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/video-attachment
+*/
+func VideoDataMessage(data []byte, contentType string) *SendRequest {
+	return dataMessage("video", data, contentType, fileNameFor("video", contentType))
+}
+
+/*
+FileMessage is a fluent helper method for creating a SendRequest containing a message with
+a file attached using the URL of the file.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/file-attachment
+*/
+func FileMessage(url string) *SendRequest {
+	return resourceMessage("file", url)
+}
+
+/*
+FileDataMessage is a fluent helper method for creating a SendRequest containing a message
+with a file attached by uploading the bytes of the file. The recipient sees fileName as
+the name of the file.
+
+	pdfBytes, _ := ioutil.ReadFile("./receipt.pdf")
+	request := FileDataMessage(pdfBytes, "application/pdf", "receipt.pdf").To("USER_ID")
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/file-attachment
+*/
+func FileDataMessage(data []byte, contentType, fileName string) *SendRequest {
+	return dataMessage("file", data, contentType, fileName)
+}
+
+/*
+DataMessage is a fluent helper method for creating a SendRequest containing a message with
+an attachment of type attachmentType ("image", "audio", "video" or "file") uploaded from data.
+The content type and file name are detected as described for NewDataPayload.
+
+	pdfBytes, _ := ioutil.ReadFile("./receipt.pdf")
+	request, err := DataMessage("file", pdfBytes)
+	if err != nil {
+		//Messenger does not accept this type of data.
+	}
+	request.WithFileName("receipt.pdf").To("USER_ID")
+*/
+func DataMessage(attachmentType string, data []byte) (*SendRequest, error) {
+	payload, err := NewDataPayload(attachmentType, data)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SendRequest{
 		Message: Message{
 			Attachment: &Attachment{
-				Type: "image",
-				Payload: ResourcePayload{
-					URL: url,
+				Type:    attachmentType,
+				Payload: payload,
+			},
+		},
+	}, nil
+}
+
+/*
+WithFileName is a fluent helper method for setting the FileName of a DataPayload or
+ReaderPayload for the message. The file name is only visible to the recipient for "file"
+attachments. It is a mutator and returns the same SendRequest on which it is called to
+support method chaining.
+*/
+func (sr *SendRequest) WithFileName(fileName string) *SendRequest {
+	if sr.Message.Attachment == nil {
+		return sr
+	}
+
+	switch payload := sr.Message.Attachment.Payload.(type) {
+	case DataPayload:
+		payload.FileName = fileName
+		sr.Message.Attachment.Payload = payload
+	case ReaderPayload:
+		payload.FileName = fileName
+		sr.Message.Attachment.Payload = payload
+	}
+
+	return sr
+}
+
+/*
+AttachmentIdMessage is a fluent helper method for creating a SendRequest containing a message
+with an attachment of type attachmentType ("image", "audio", "video" or "file") that was
+previously uploaded with Client.UploadAttachment, or sent with Reusable.
+
+See https://developers.facebook.com/docs/messenger-platform/reference/attachment-upload-api
+*/
+func AttachmentIdMessage(attachmentType, attachmentId string) *SendRequest {
+	return &SendRequest{
+		Message: Message{
+			Attachment: &Attachment{
+				Type: attachmentType,
+				Payload: AttachmentIdPayload{
+					AttachmentId: attachmentId,
 				},
 			},
 		},
@@ -38,32 +183,96 @@ func ImageMessage(url string) *SendRequest {
 }
 
 /*
-ImageDataMessage is a fluent helper method for creating a SendRequest containing a message
-with an image attached by uploading the bytes of the image.
+Reusable is a fluent helper method for marking the attachment of a message as reusable.
+The AttachmentId of the SendResponse can then be used with AttachmentIdMessage. It is
+a mutator and returns the same SendRequest on which it is called to support method chaining.
+*/
+func (sr *SendRequest) Reusable() *SendRequest {
+	if sr.Message.Attachment != nil {
+		sr.Message.Attachment = reusableAttachment(sr.Message.Attachment)
+	}
 
-	imageBytes, _ := ioutil.ReadFile("./cool-pic.png")
-	request := ImageDataMessage(imageBytes, "image/png").To("USER_ID")
+	return sr
+}
 
-Detecting the content type of a file dynamically, or converting to one of the image formats
-supported by Facebook is the responsibility of the user.
+// reusableAttachment returns a copy of attachment with IsReusable set on its payload.
+func reusableAttachment(attachment *Attachment) *Attachment {
+	reusable := *attachment
+
+	switch payload := attachment.Payload.(type) {
+	case ResourcePayload:
+		payload.IsReusable = true
+		reusable.Payload = payload
+	case DataPayload:
+		payload.IsReusable = true
+		reusable.Payload = payload
+	case ReaderPayload:
+		payload.IsReusable = true
+		reusable.Payload = payload
+	}
 
-See https://developers.facebook.com/docs/messenger-platform/send-api-reference/image-attachment
+	return &reusable
+}
+
+/*
+ReaderMessage is a fluent helper method for creating a SendRequest containing a message with
+an attachment of type attachmentType ("image", "audio", "video" or "file") streamed from
+reader. Pass a size of zero if it is unknown.
+
+	videoFile, _ := os.Open("./big-video.mp4")
+	defer videoFile.Close()
+	stat, _ := videoFile.Stat()
+	request := ReaderMessage("video", videoFile, stat.Size(), "video/mp4", "big-video.mp4").To("USER_ID")
 */
-func ImageDataMessage(data []byte, contentType string) *SendRequest {
+func ReaderMessage(attachmentType string, reader io.Reader, size int64, contentType, fileName string) *SendRequest {
 	return &SendRequest{
 		Message: Message{
 			Attachment: &Attachment{
-				Type: "image",
+				Type: attachmentType,
+				Payload: ReaderPayload{
+					Reader:      reader,
+					Size:        size,
+					ContentType: contentType,
+					FileName:    fileName,
+				},
+			},
+		},
+	}
+}
+
+func resourceMessage(attachmentType, url string) *SendRequest {
+	return &SendRequest{
+		Message: Message{
+			Attachment: &Attachment{
+				Type: attachmentType,
+				Payload: ResourcePayload{
+					URL: url,
+				},
+			},
+		},
+	}
+}
+
+func dataMessage(attachmentType string, data []byte, contentType, fileName string) *SendRequest {
+	return &SendRequest{
+		Message: Message{
+			Attachment: &Attachment{
+				Type: attachmentType,
 				Payload: DataPayload{
 					Data:        data,
 					ContentType: contentType,
-					FileName:    strings.Replace(contentType, "/", ".", -1), //Should yield file names like "image.png"
+					FileName:    fileName,
 				},
 			},
 		},
 	}
 }
 
+// fileNameFor yields file names like "audio.mp3" for an attachment with the content type "audio/mpeg".
+func fileNameFor(attachmentType, contentType string) string {
+	return attachmentType + FileExtension(contentType)
+}
+
 /*
 ButtonTemplateMessage is a fluent helper method for creating a SendRequest containing text
 and buttons to request input from the user.
@@ -106,6 +315,94 @@ func GenericTemplateMessage(elements ...*GenericElement) *SendRequest {
 	}
 }
 
+/*
+WithImageAspectRatio is a fluent helper method for setting the ImageAspectRatio of a
+GenericPayload for the message to "horizontal" or "square". Messages without a
+GenericPayload are left unchanged. It is a mutator and returns the same SendRequest on
+which it is called to support method chaining.
+*/
+func (sr *SendRequest) WithImageAspectRatio(ratio string) *SendRequest {
+	return sr.updateGenericPayload(func(generic *GenericPayload) {
+		generic.ImageAspectRatio = ratio
+	})
+}
+
+/*
+Sharable is a fluent helper method for setting Sharable on a GenericPayload for the message,
+which shows the native share button for the message. Messages without a GenericPayload are
+left unchanged. It is a mutator and returns the same SendRequest on which it is called to
+support method chaining.
+*/
+func (sr *SendRequest) Sharable() *SendRequest {
+	return sr.updateGenericPayload(func(generic *GenericPayload) {
+		generic.Sharable = true
+	})
+}
+
+// updateGenericPayload calls update with the GenericPayload of the message, if it has one,
+// and stores the result back in the attachment.
+func (sr *SendRequest) updateGenericPayload(update func(generic *GenericPayload)) *SendRequest {
+	if sr.Message.Attachment == nil {
+		return sr
+	}
+
+	generic, ok := sr.Message.Attachment.Payload.(GenericPayload)
+	if !ok {
+		return sr
+	}
+
+	update(&generic)
+	sr.Message.Attachment.Payload = generic
+
+	return sr
+}
+
+// NewGenericElement is a fluent helper method for creating a GenericElement with a title.
+func NewGenericElement(title string) *GenericElement {
+	return &GenericElement{Title: title}
+}
+
+// WithSubtitle is a fluent helper method for setting Subtitle. It is a mutator and returns
+// the same GenericElement on which it is called to support method chaining.
+func (ge *GenericElement) WithSubtitle(subtitle string) *GenericElement {
+	ge.Subtitle = subtitle
+
+	return ge
+}
+
+// WithImageURL is a fluent helper method for setting ImageURL. It is a mutator and returns
+// the same GenericElement on which it is called to support method chaining.
+func (ge *GenericElement) WithImageURL(imageURL string) *GenericElement {
+	ge.ImageURL = imageURL
+
+	return ge
+}
+
+// WithItemURL is a fluent helper method for setting ItemURL, which is opened when the element
+// is tapped. It is a mutator and returns the same GenericElement on which it is called to
+// support method chaining.
+func (ge *GenericElement) WithItemURL(itemURL string) *GenericElement {
+	ge.ItemURL = itemURL
+
+	return ge
+}
+
+// WithDefaultAction is a fluent helper method for setting DefaultAction. It is a mutator and
+// returns the same GenericElement on which it is called to support method chaining.
+func (ge *GenericElement) WithDefaultAction(action *DefaultAction) *GenericElement {
+	ge.DefaultAction = action
+
+	return ge
+}
+
+// WithButtons is a fluent helper method for setting Buttons. It is a mutator and returns
+// the same GenericElement on which it is called to support method chaining.
+func (ge *GenericElement) WithButtons(buttons ...*Button) *GenericElement {
+	ge.Buttons = buttons
+
+	return ge
+}
+
 /*
 ReceiptTemplateMessage is a fluent helper method for creating a SendRequest containing
 a detailed order confirmation.
@@ -172,6 +469,163 @@ func (sr *SendRequest) WithReceiptAdjustments(adjustments ...*ReceiptAdjustment)
 	return sr
 }
 
+/*
+ListTemplateMessage is a fluent helper method for creating a SendRequest containing a
+vertical list of 2 to 4 elements. Use Validate on the ListPayload to check the limits
+of the list template before sending.
+
+See https://developers.facebook.com/docs/messenger-platform/send-messages/template/list
+*/
+func ListTemplateMessage(elements ...*ListElement) *SendRequest {
+	return &SendRequest{
+		Message: Message{
+			Attachment: &Attachment{
+				Type: "template",
+				Payload: &ListPayload{
+					TemplateType: "list",
+					Elements:     elements,
+				},
+			},
+		},
+	}
+}
+
+/*
+WithListTopElementStyle is a fluent helper method for setting the TopElementStyle of a
+ListPayload for the message to "large" or "compact". Messages without a ListPayload are
+left unchanged. It is a mutator and returns the same SendRequest on which it is called to
+support method chaining.
+*/
+func (sr *SendRequest) WithListTopElementStyle(style string) *SendRequest {
+	return sr.updateListPayload(func(list *ListPayload) {
+		list.TopElementStyle = style
+	})
+}
+
+/*
+WithListButton is a fluent helper method for setting the button shown below the elements
+of a ListPayload for the message, such as a "View More" button. Messages without a
+ListPayload are left unchanged. It is a mutator and returns the same SendRequest on which
+it is called to support method chaining.
+*/
+func (sr *SendRequest) WithListButton(button *Button) *SendRequest {
+	return sr.updateListPayload(func(list *ListPayload) {
+		list.Buttons = []*Button{button}
+	})
+}
+
+// updateListPayload calls update with the ListPayload of the message, if it has one.
+func (sr *SendRequest) updateListPayload(update func(list *ListPayload)) *SendRequest {
+	if sr.Message.Attachment == nil {
+		return sr
+	}
+
+	list, ok := sr.Message.Attachment.Payload.(*ListPayload)
+	if !ok || list == nil {
+		return sr
+	}
+
+	update(list)
+
+	return sr
+}
+
+// NewListElement is a fluent helper method for creating a ListElement with a title.
+func NewListElement(title string) *ListElement {
+	return &ListElement{Title: title}
+}
+
+// WithSubtitle is a fluent helper method for setting Subtitle. It is a mutator and returns
+// the same ListElement on which it is called to support method chaining.
+func (le *ListElement) WithSubtitle(subtitle string) *ListElement {
+	le.Subtitle = subtitle
+
+	return le
+}
+
+// WithImageURL is a fluent helper method for setting ImageURL. It is a mutator and returns
+// the same ListElement on which it is called to support method chaining.
+func (le *ListElement) WithImageURL(imageURL string) *ListElement {
+	le.ImageURL = imageURL
+
+	return le
+}
+
+// WithDefaultAction is a fluent helper method for setting DefaultAction. It is a mutator and
+// returns the same ListElement on which it is called to support method chaining.
+func (le *ListElement) WithDefaultAction(action *DefaultAction) *ListElement {
+	le.DefaultAction = action
+
+	return le
+}
+
+// WithButton is a fluent helper method for setting the one button of the element. It is a
+// mutator and returns the same ListElement on which it is called to support method chaining.
+func (le *ListElement) WithButton(button *Button) *ListElement {
+	le.Buttons = []*Button{button}
+
+	return le
+}
+
+// URLDefaultAction is a fluent helper method for creating a DefaultAction that opens url
+// when the element is tapped.
+func URLDefaultAction(url string) *DefaultAction {
+	return &DefaultAction{
+		Type: "web_url",
+		URL:  url,
+	}
+}
+
+/*
+MediaTemplateMessage is a fluent helper method for creating a SendRequest containing an
+image or video with buttons. The media template has exactly one element.
+
+	element := MediaAttachmentElement("image", "1857777774821032").
+		WithButtons(URLButton("View Website", "https://example.com"))
+	request := MediaTemplateMessage(element).To("USER_ID")
+
+See https://developers.facebook.com/docs/messenger-platform/send-messages/template/media
+*/
+func MediaTemplateMessage(element *MediaElement) *SendRequest {
+	return &SendRequest{
+		Message: Message{
+			Attachment: &Attachment{
+				Type: "template",
+				Payload: MediaPayload{
+					TemplateType: "media",
+					Elements:     []*MediaElement{element},
+				},
+			},
+		},
+	}
+}
+
+// MediaAttachmentElement is a fluent helper method for creating a MediaElement of type
+// mediaType ("image" or "video") using the Id of an uploaded attachment.
+func MediaAttachmentElement(mediaType, attachmentId string) *MediaElement {
+	return &MediaElement{
+		MediaType:    mediaType,
+		AttachmentId: attachmentId,
+	}
+}
+
+// MediaURLElement is a fluent helper method for creating a MediaElement of type mediaType
+// ("image" or "video") using the Facebook URL of the media.
+func MediaURLElement(mediaType, url string) *MediaElement {
+	return &MediaElement{
+		MediaType: mediaType,
+		URL:       url,
+	}
+}
+
+// WithButtons is a fluent helper method for setting Buttons. It is a mutator and returns
+// the same MediaElement on which it is called to support method chaining.
+func (me *MediaElement) WithButtons(buttons ...*Button) *MediaElement {
+	me.Buttons = buttons
+
+	return me
+}
+
 // URLButton is a fluent helper method for creating a button with type "web_url" for
 // use in a message with a button template or generic template attachment.
 func URLButton(title, url string) *Button {
@@ -192,6 +646,88 @@ func PostbackButton(title, payload string) *Button {
 	}
 }
 
+// CallButton is a fluent helper method for creating a button with type "phone_number" that
+// calls phoneNumber (e.g. "+16505551234") when tapped.
+func CallButton(title, phoneNumber string) *Button {
+	return &Button{
+		Type:    "phone_number",
+		Title:   title,
+		Payload: phoneNumber,
+	}
+}
+
+/*
+ShareButton is a fluent helper method for creating a button with type "element_share" that
+lets the user share the message. Use WithShareContents to share a different message.
+
+See https://developers.facebook.com/docs/messenger-platform/send-messages/buttons#share
+*/
+func ShareButton() *Button {
+	return &Button{
+		Type: "element_share",
+	}
+}
+
+// LoginButton is a fluent helper method for creating a button with type "account_link" that
+// opens url to log the user in to your account system.
+func LoginButton(url string) *Button {
+	return &Button{
+		Type: "account_link",
+		URL:  url,
+	}
+}
+
+// LogoutButton is a fluent helper method for creating a button with type "account_unlink".
+func LogoutButton() *Button {
+	return &Button{
+		Type: "account_unlink",
+	}
+}
+
+/*
+WithShareContents is a fluent helper method for setting the message shared by a button with
+type "element_share", which must be a generic template message with at most one URL button.
+It is a mutator and returns the same Button on which it is called to support method chaining.
+
+	contents := GenericTemplateMessage(element)
+	button := ShareButton().WithShareContents(contents)
+*/
+func (b *Button) WithShareContents(contents *SendRequest) *Button {
+	b.ShareContents = &ShareContents{
+		Attachment: contents.Message.Attachment,
+	}
+
+	return b
+}
+
+// WithWebviewHeightRatio is a fluent helper method for setting the height of the webview
+// opened by a "web_url" button to "compact", "tall" or "full". It is a mutator and returns
+// the same Button on which it is called to support method chaining.
+func (b *Button) WithWebviewHeightRatio(ratio string) *Button {
+	b.WebviewHeightRatio = ratio
+
+	return b
+}
+
+// WithMessengerExtensions is a fluent helper method for enabling the Messenger Extensions SDK
+// in the webview opened by a "web_url" button. Clients that don't support it open fallbackURL
+// instead. It is a mutator and returns the same Button on which it is called to support method chaining.
+func (b *Button) WithMessengerExtensions(fallbackURL string) *Button {
+	b.MessengerExtensions = true
+	b.FallbackURL = fallbackURL
+
+	return b
+}
+
+// HideWebviewShareButton is a fluent helper method for hiding the share button in the webview
+// opened by a "web_url" button. It is a mutator and returns the same Button on which it is
+// called to support method chaining.
+func (b *Button) HideWebviewShareButton() *Button {
+	b.WebviewShareButton = "hide"
+
+	return b
+}
+
 // To is a fluent helper method for setting Recipient. It is a mutator
 // and returns the same SendRequest on which it is called to support method chaining.
 func (sr *SendRequest) To(userId string) *SendRequest {
@@ -231,6 +767,38 @@ func (sr *SendRequest) NoPush() *SendRequest {
 	return sr
 }
 
+// AsResponse is a fluent helper method for setting MessagingType to "RESPONSE", for messages
+// sent in response to a message from the user. It is a mutator and returns the same SendRequest
+// on which it is called to support method chaining.
+func (sr *SendRequest) AsResponse() *SendRequest {
+	sr.MessagingType = "RESPONSE"
+
+	return sr
+}
+
+// AsUpdate is a fluent helper method for setting MessagingType to "UPDATE", for messages sent
+// proactively within the standard messaging window. It is a mutator and returns the same
+// SendRequest on which it is called to support method chaining.
+func (sr *SendRequest) AsUpdate() *SendRequest {
+	sr.MessagingType = "UPDATE"
+
+	return sr
+}
+
+/*
+WithTag is a fluent helper method for setting Tag, and setting MessagingType to "MESSAGE_TAG",
+for messages sent outside the standard messaging window. It is a mutator and returns the
+same SendRequest on which it is called to support method chaining.
+
+See https://developers.facebook.com/docs/messenger-platform/send-messages/message-tags
+*/
+func (sr *SendRequest) WithTag(tag MessageTag) *SendRequest {
+	sr.MessagingType = "MESSAGE_TAG"
+	sr.Tag = tag
+
+	return sr
+}
+
 // TextReply is a fluent helper method for creating a QuickReply with content type "text".
 func TextReply(title, payload string) *QuickReply {
 	return &QuickReply{
@@ -258,6 +826,22 @@ func LocationReply() *QuickReply {
 	}
 }
 
+// PhoneNumberReply is a fluent helper method for creating a QuickReply with content type
+// "user_phone_number", which offers the phone number from the user's profile.
+func PhoneNumberReply() *QuickReply {
+	return &QuickReply{
+		ContentType: "user_phone_number",
+	}
+}
+
+// EmailReply is a fluent helper method for creating a QuickReply with content type
+// "user_email", which offers the email address from the user's profile.
+func EmailReply() *QuickReply {
+	return &QuickReply{
+		ContentType: "user_email",
+	}
+}
+
 // WithQuickReplies is a fluent helper method for setting the quick replies to
 // a message. It is not additive, it replaces any existing quick replies.
 func (sr *SendRequest) WithQuickReplies(replies ...*QuickReply) *SendRequest {
@@ -266,15 +850,64 @@ func (sr *SendRequest) WithQuickReplies(replies ...*QuickReply) *SendRequest {
 	return sr
 }
 
+// TypingOn is a fluent helper method for creating a SenderActionRequest that turns
+// typing indicators on.
+func TypingOn() *SenderActionRequest {
+	return &SenderActionRequest{SenderAction: "typing_on"}
+}
+
+// TypingOff is a fluent helper method for creating a SenderActionRequest that turns
+// typing indicators off.
+func TypingOff() *SenderActionRequest {
+	return &SenderActionRequest{SenderAction: "typing_off"}
+}
+
+// MarkSeen is a fluent helper method for creating a SenderActionRequest that marks the
+// last message from the user as seen.
+func MarkSeen() *SenderActionRequest {
+	return &SenderActionRequest{SenderAction: "mark_seen"}
+}
+
+// To is a fluent helper method for setting Recipient. It is a mutator and returns the
+// same SenderActionRequest on which it is called to support method chaining.
+func (sar *SenderActionRequest) To(userId string) *SenderActionRequest {
+	sar.Recipient = Recipient{Id: userId}
+
+	return sar
+}
+
 /*
 SendRequest is the top level structure for representing any type of message to send.
 
 See https://developers.facebook.com/docs/messenger-platform/send-api-reference#request
 */
 type SendRequest struct {
-	Recipient        Recipient `json:"recipient" binding:"required"`
-	Message          Message   `json:"message" binding:"required"`
-	NotificationType string    `json:"notification_type,omitempty"`
+	MessagingType    string     `json:"messaging_type,omitempty"`
+	Recipient        Recipient  `json:"recipient" binding:"required"`
+	Message          Message    `json:"message" binding:"required"`
+	NotificationType string     `json:"notification_type,omitempty"`
+	Tag              MessageTag `json:"tag,omitempty"`
+}
+
+// MessageTag identifies the purpose of a message sent outside the standard messaging window.
+type MessageTag string
+
+// The message tags allowed for messages with MessagingType "MESSAGE_TAG".
+const (
+	ConfirmedEventUpdate MessageTag = "CONFIRMED_EVENT_UPDATE"
+	PostPurchaseUpdate   MessageTag = "POST_PURCHASE_UPDATE"
+	AccountUpdate        MessageTag = "ACCOUNT_UPDATE"
+	HumanAgent           MessageTag = "HUMAN_AGENT"
+)
+
+/*
+SenderActionRequest is used to set typing indicators or send read receipts.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/sender-actions
+*/
+type SenderActionRequest struct {
+	Recipient    Recipient `json:"recipient" binding:"required"`
+	SenderAction string    `json:"sender_action" binding:"required"`
 }
 
 // Recipient identifies the user to send to. Either Id or PhoneNumber must be set, but not both.
@@ -303,13 +936,14 @@ ResourcePayload is used to hold the URL of a resource (image, file, etc.) to att
 See https://developers.facebook.com/docs/messenger-platform/send-api-reference/image-attachment
 */
 type ResourcePayload struct {
-	URL string `json:"url" binding:"required"`
+	URL        string `json:"url" binding:"required"`
+	IsReusable bool   `json:"is_reusable,omitempty"`
 }
 
 /*
 DataPayload is used to hold the bytes of a resource (image, file, etc.) to upload and attach
-to a message. All fields are required. FileName will only be visible to the recipient when
-type of the attachment is "file".
+to a message. Data, ContentType and FileName are required. FileName will only be visible to
+the recipient when type of the attachment is "file".
 
 See https://developers.facebook.com/docs/messenger-platform/send-api-reference/image-attachment
 */
@@ -317,6 +951,33 @@ type DataPayload struct {
 	Data        []byte `json:"-"`
 	ContentType string `json:"-"`
 	FileName    string `json:"-"`
+	IsReusable  bool   `json:"is_reusable,omitempty"`
+}
+
+/*
+ReaderPayload is used to stream a resource (video, file, etc.) from an io.Reader to upload
+and attach to a message, without holding all of it in memory. ContentType and FileName are
+required. Size is optional, and when it is greater than zero it is used to set the
+Content-Length of the request and only that many bytes are read.
+
+If the request is retried, Reader must be an io.Seeker so that it can be rewound to where
+it was when the request was first made. Otherwise the request is not retried.
+*/
+type ReaderPayload struct {
+	Reader      io.Reader `json:"-"`
+	Size        int64     `json:"-"`
+	ContentType string    `json:"-"`
+	FileName    string    `json:"-"`
+	IsReusable  bool      `json:"is_reusable,omitempty"`
+}
+
+/*
+AttachmentIdPayload is used to attach a previously uploaded attachment to a message.
+
+See https://developers.facebook.com/docs/messenger-platform/reference/attachment-upload-api
+*/
+type AttachmentIdPayload struct {
+	AttachmentId string `json:"attachment_id" binding:"required"`
 }
 
 /*
@@ -330,12 +991,27 @@ type ButtonPayload struct {
 	Buttons      []*Button `json:"buttons" binding:"required"`
 }
 
-// Button represents a single button in a structured message using the button template.
+/*
+Button represents a single button in a structured message. Title is required for all types
+of buttons except "element_share", "account_link" and "account_unlink".
+
+See https://developers.facebook.com/docs/messenger-platform/send-messages/buttons
+*/
 type Button struct {
-	Type    string `json:"type" binding:"required"`
-	Title   string `json:"title" binding:"required"`
-	URL     string `json:"url,omitempty"`
-	Payload string `json:"payload,omitempty"`
+	Type                string         `json:"type" binding:"required"`
+	Title               string         `json:"title,omitempty"`
+	URL                 string         `json:"url,omitempty"`
+	Payload             string         `json:"payload,omitempty"`
+	WebviewHeightRatio  string         `json:"webview_height_ratio,omitempty"`
+	MessengerExtensions bool           `json:"messenger_extensions,omitempty"`
+	FallbackURL         string         `json:"fallback_url,omitempty"`
+	WebviewShareButton  string         `json:"webview_share_button,omitempty"`
+	ShareContents       *ShareContents `json:"share_contents,omitempty"`
+}
+
+// ShareContents holds the message shared by a button with type "element_share".
+type ShareContents struct {
+	Attachment *Attachment `json:"attachment" binding:"required"`
 }
 
 /*
@@ -344,16 +1020,71 @@ GenericPayload is used to build a structured message using the generic template.
 See https://developers.facebook.com/docs/messenger-platform/send-api-reference/generic-template
 */
 type GenericPayload struct {
-	TemplateType string            `json:"template_type" binding:"required"`
-	Elements     []*GenericElement `json:"elements" binding:"required"`
+	TemplateType     string            `json:"template_type" binding:"required"`
+	ImageAspectRatio string            `json:"image_aspect_ratio,omitempty"`
+	Sharable         bool              `json:"sharable,omitempty"`
+	Elements         []*GenericElement `json:"elements" binding:"required"`
 }
 
-// GenericElement represents one item in the carousel of a generic template message.
+// GenericElement represents one item in the carousel of a generic template message. Only
+// Title is required. Set DefaultAction or ItemURL to make the element tappable without buttons.
 type GenericElement struct {
-	Title    string    `json:"title" binding:"required"`
-	ImageURL string    `json:"image_url" binding:"required"`
-	Subtitle string    `json:"subtitle" binding:"required"`
-	Buttons  []*Button `json:"buttons" binding:"required"`
+	Title         string         `json:"title" binding:"required"`
+	ItemURL       string         `json:"item_url,omitempty"`
+	ImageURL      string         `json:"image_url,omitempty"`
+	Subtitle      string         `json:"subtitle,omitempty"`
+	DefaultAction *DefaultAction `json:"default_action,omitempty"`
+	Buttons       []*Button      `json:"buttons,omitempty"`
+}
+
+/*
+ListPayload is used to build a structured message using the list template.
+
+See https://developers.facebook.com/docs/messenger-platform/send-messages/template/list
+*/
+type ListPayload struct {
+	TemplateType    string         `json:"template_type" binding:"required"`
+	TopElementStyle string         `json:"top_element_style,omitempty"`
+	Elements        []*ListElement `json:"elements" binding:"required"`
+	Buttons         []*Button      `json:"buttons,omitempty"`
+}
+
+// ListElement represents one item in a list template message.
+type ListElement struct {
+	Title         string         `json:"title" binding:"required"`
+	Subtitle      string         `json:"subtitle,omitempty"`
+	ImageURL      string         `json:"image_url,omitempty"`
+	DefaultAction *DefaultAction `json:"default_action,omitempty"`
+	Buttons       []*Button      `json:"buttons,omitempty"`
+}
+
+// DefaultAction represents what happens when the user taps an element of a template,
+// rather than one of its buttons.
+type DefaultAction struct {
+	Type                string `json:"type" binding:"required"`
+	URL                 string `json:"url" binding:"required"`
+	WebviewHeightRatio  string `json:"webview_height_ratio,omitempty"`
+	MessengerExtensions bool   `json:"messenger_extensions,omitempty"`
+	FallbackURL         string `json:"fallback_url,omitempty"`
+}
+
+/*
+MediaPayload is used to build a structured message using the media template.
+
+See https://developers.facebook.com/docs/messenger-platform/send-messages/template/media
+*/
+type MediaPayload struct {
+	TemplateType string          `json:"template_type" binding:"required"`
+	Elements     []*MediaElement `json:"elements" binding:"required"`
+}
+
+// MediaElement represents the image or video of a media template message. Either
+// AttachmentId or URL must be set, but not both.
+type MediaElement struct {
+	MediaType    string    `json:"media_type" binding:"required"`
+	AttachmentId string    `json:"attachment_id,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	Buttons      []*Button `json:"buttons,omitempty"`
 }
 
 /*
@@ -425,9 +1156,19 @@ SendResponse is returned when sending a SendRequest.
 See https://developers.facebook.com/docs/messenger-platform/send-api-reference#response
 */
 type SendResponse struct {
-	RecipientId string     `json:"recipient_id" binding:"required"`
-	MessageId   string     `json:"message_id" binding:"required"`
-	Error       *SendError `json:"error"`
+	RecipientId  string     `json:"recipient_id" binding:"required"`
+	MessageId    string     `json:"message_id" binding:"required"`
+	AttachmentId string     `json:"attachment_id,omitempty"`
+	Error        *SendError `json:"error"`
+}
+
+/*
+UploadResponse is returned when uploading an attachment.
+
+See https://developers.facebook.com/docs/messenger-platform/reference/attachment-upload-api
+*/
+type UploadResponse struct {
+	AttachmentId string `json:"attachment_id" binding:"required"`
 }
 
 /*
@@ -436,11 +1177,13 @@ SendError indicates an error returned from Facebook.
 See https://developers.facebook.com/docs/messenger-platform/send-api-reference#errors
 */
 type SendError struct {
-	Message   string `json:"message" binding:"required"`
-	Type      string `json:"type" binding:"required"`
-	Code      int    `json:"code" binding:"required"`
-	ErrorData string `json:"error_data" binding:"required"`
-	FBTraceId string `json:"fbtrace_id" binding:"required"`
+	Message      string `json:"message" binding:"required"`
+	Type         string `json:"type" binding:"required"`
+	Code         int    `json:"code" binding:"required"`
+	ErrorSubcode int    `json:"error_subcode,omitempty"`
+	ErrorData    string `json:"error_data" binding:"required"`
+	IsTransient  bool   `json:"is_transient,omitempty"`
+	FBTraceId    string `json:"fbtrace_id" binding:"required"`
 }
 
 /*------------------------------------------------------
@@ -476,6 +1219,7 @@ type MessagingEntry struct {
 	Timestamp int              `json:"timestamp"`
 	Message   *CallbackMessage `json:"message"`
 	Delivery  *Delivery        `json:"delivery"`
+	Read      *Read            `json:"read"`
 	Postback  *Postback        `json:"postback"`
 	OptIn     *OptIn           `json:"optin"`
 }
@@ -520,11 +1264,55 @@ type Coordinates struct {
 	Long float64 `json:"long" binding:"required"`
 }
 
-// CallbackQuickReply holds the developer defined payload of a quick reply sent by the user.
+/*
+CallbackQuickReply holds the payload of a quick reply sent by the user. The payload is
+developer defined, except for quick replies with content type "user_phone_number" or
+"user_email", where it is the phone number or email address the user chose to share. Use
+PhoneNumber or Email to get them.
+*/
 type CallbackQuickReply struct {
 	Payload string `json:"payload" binding:"required"`
 }
 
+// phoneNumberPattern matches phone numbers in E.164 form, like "+16505551234".
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+/*
+PhoneNumber returns the payload and true if it is a phone number in E.164 form, like
+"+16505551234", as sent when the user taps a quick reply with content type
+"user_phone_number". Callbacks do not say which content type of quick reply was tapped, so
+the payload is checked to look like a phone number instead. Only call it for replies to a
+message sent with PhoneNumberReply, since a developer defined payload could look like one.
+*/
+func (qr *CallbackQuickReply) PhoneNumber() (string, bool) {
+	if !phoneNumberPattern.MatchString(qr.Payload) {
+		return "", false
+	}
+
+	return qr.Payload, true
+}
+
+/*
+Email returns the payload and true if it is an email address with a dot in its domain, like
+"user@example.com", as sent when the user taps a quick reply with content type "user_email".
+Callbacks do not say which content type of quick reply was tapped, so the payload is checked
+to look like an email address instead. Only call it for replies to a message sent with
+EmailReply, since a developer defined payload could look like one.
+*/
+func (qr *CallbackQuickReply) Email() (string, bool) {
+	address, err := mail.ParseAddress(qr.Payload)
+	if err != nil || address.Name != "" || address.Address != qr.Payload {
+		return "", false
+	}
+
+	domain := qr.Payload[strings.LastIndex(qr.Payload, "@")+1:]
+	if !strings.Contains(strings.Trim(domain, "."), ".") {
+		return "", false
+	}
+
+	return qr.Payload, true
+}
+
 /*
 Delivery holds information about which of the messages that you've sent have been delivered.
 
@@ -536,6 +1324,17 @@ type Delivery struct {
 	Sequence   int      `json:"seq" bindging:"required"`
 }
 
+/*
+Read holds information about which of the messages that you've sent have been read. All
+messages sent before Watermark, a timestamp in milliseconds, were read by the user.
+
+See https://developers.facebook.com/docs/messenger-platform/webhook-reference/message-read
+*/
+type Read struct {
+	Watermark int `json:"watermark" binding:"required"`
+	Sequence  int `json:"seq"`
+}
+
 /*
 Postback holds the data defined for buttons the user taps.
 
@@ -554,6 +1353,161 @@ type OptIn struct {
 	Ref string `json:"ref" binding:"required"`
 }
 
+/*------------------------------------------------------
+Messenger Profile
+------------------------------------------------------*/
+
+// NewMessengerProfile is a fluent helper method for creating an empty MessengerProfile.
+func NewMessengerProfile() *MessengerProfile {
+	return &MessengerProfile{}
+}
+
+// WithGetStarted is a fluent helper method for setting the payload of the postback sent when
+// the user taps the Get Started button. It is a mutator and returns the same MessengerProfile
+// on which it is called to support method chaining.
+func (mp *MessengerProfile) WithGetStarted(payload string) *MessengerProfile {
+	mp.GetStarted = &GetStarted{Payload: payload}
+
+	return mp
+}
+
+// WithGreeting is a fluent helper method for setting the greeting text for each locale. It is
+// not additive, it replaces any existing greetings.
+func (mp *MessengerProfile) WithGreeting(greetings ...*Greeting) *MessengerProfile {
+	mp.Greeting = greetings
+
+	return mp
+}
+
+// WithPersistentMenu is a fluent helper method for setting the persistent menu for each locale.
+// It is not additive, it replaces any existing menus.
+func (mp *MessengerProfile) WithPersistentMenu(menus ...*PersistentMenu) *MessengerProfile {
+	mp.PersistentMenu = menus
+
+	return mp
+}
+
+// WithWhitelistedDomains is a fluent helper method for setting the domains allowed in webviews.
+// It is not additive, it replaces any existing domains.
+func (mp *MessengerProfile) WithWhitelistedDomains(domains ...string) *MessengerProfile {
+	mp.WhitelistedDomains = domains
+
+	return mp
+}
+
+// GreetingText is a fluent helper method for creating a Greeting for the default locale.
+func GreetingText(text string) *Greeting {
+	return LocalizedGreetingText("default", text)
+}
+
+// LocalizedGreetingText is a fluent helper method for creating a Greeting for a locale like "en_US".
+func LocalizedGreetingText(locale, text string) *Greeting {
+	return &Greeting{
+		Locale: locale,
+		Text:   text,
+	}
+}
+
+// DefaultPersistentMenu is a fluent helper method for creating a PersistentMenu for the default locale.
+func DefaultPersistentMenu(items ...*MenuItem) *PersistentMenu {
+	return LocalizedPersistentMenu("default", items...)
+}
+
+// LocalizedPersistentMenu is a fluent helper method for creating a PersistentMenu for a locale like "en_US".
+func LocalizedPersistentMenu(locale string, items ...*MenuItem) *PersistentMenu {
+	return &PersistentMenu{
+		Locale:        locale,
+		CallToActions: items,
+	}
+}
+
+// DisableComposerInput is a fluent helper method for hiding the composer so the user can only
+// interact using the persistent menu. It is a mutator and returns the same PersistentMenu on
+// which it is called to support method chaining.
+func (pm *PersistentMenu) DisableComposerInput() *PersistentMenu {
+	pm.ComposerInputDisabled = true
+
+	return pm
+}
+
+// URLMenuItem is a fluent helper method for creating a menu item with type "web_url".
+func URLMenuItem(title, url string) *MenuItem {
+	return &MenuItem{
+		Type:  "web_url",
+		Title: title,
+		URL:   url,
+	}
+}
+
+// PostbackMenuItem is a fluent helper method for creating a menu item with type "postback".
+func PostbackMenuItem(title, payload string) *MenuItem {
+	return &MenuItem{
+		Type:    "postback",
+		Title:   title,
+		Payload: payload,
+	}
+}
+
+// NestedMenuItem is a fluent helper method for creating a menu item with type "nested" that
+// opens a submenu of items.
+func NestedMenuItem(title string, items ...*MenuItem) *MenuItem {
+	return &MenuItem{
+		Type:          "nested",
+		Title:         title,
+		CallToActions: items,
+	}
+}
+
+// MessengerProfileField identifies a field of the Messenger Profile to get or delete.
+type MessengerProfileField string
+
+// The fields of the Messenger Profile.
+const (
+	GetStartedField         MessengerProfileField = "get_started"
+	GreetingField           MessengerProfileField = "greeting"
+	PersistentMenuField     MessengerProfileField = "persistent_menu"
+	WhitelistedDomainsField MessengerProfileField = "whitelisted_domains"
+)
+
+/*
+MessengerProfile holds the properties of a page's Messenger conversations.
+
+See https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api
+*/
+type MessengerProfile struct {
+	GetStarted         *GetStarted       `json:"get_started,omitempty"`
+	Greeting           []*Greeting       `json:"greeting,omitempty"`
+	PersistentMenu     []*PersistentMenu `json:"persistent_menu,omitempty"`
+	WhitelistedDomains []string          `json:"whitelisted_domains,omitempty"`
+}
+
+// GetStarted holds the payload of the postback sent when the user taps the Get Started button.
+type GetStarted struct {
+	Payload string `json:"payload" binding:"required"`
+}
+
+// Greeting holds the text shown to users before they start a conversation, for one locale.
+type Greeting struct {
+	Locale string `json:"locale" binding:"required"`
+	Text   string `json:"text" binding:"required"`
+}
+
+// PersistentMenu holds the menu that is always available in the conversation, for one locale.
+type PersistentMenu struct {
+	Locale                string      `json:"locale" binding:"required"`
+	ComposerInputDisabled bool        `json:"composer_input_disabled"`
+	CallToActions         []*MenuItem `json:"call_to_actions,omitempty"`
+}
+
+// MenuItem represents one item in a persistent menu, or a submenu of items when its Type is "nested".
+type MenuItem struct {
+	Type          string      `json:"type" binding:"required"`
+	Title         string      `json:"title" binding:"required"`
+	URL           string      `json:"url,omitempty"`
+	Payload       string      `json:"payload,omitempty"`
+	CallToActions []*MenuItem `json:"call_to_actions,omitempty"`
+}
+
 /*------------------------------------------------------
 User Profile
 ------------------------------------------------------*/
@@ -30,6 +30,54 @@ var _ = Describe("Callback Models", func() {
 			Expect(message.QuickReply.Payload).To(Equal("DEVELOPER_DEFINED_PAYLOAD"))
 		})
 
+		It("should unmarshal a callback with a phone number quick reply", func() {
+			var cb Callback
+			loadCallback("message-with-phone-number-quick-reply.json", &cb)
+
+			quickReply := cb.Entries[0].Messaging[0].Message.QuickReply
+
+			phoneNumber, ok := quickReply.PhoneNumber()
+			Expect(ok).To(BeTrue())
+			Expect(phoneNumber).To(Equal("+16505551234"))
+
+			_, ok = quickReply.Email()
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should unmarshal a callback with an email quick reply", func() {
+			var cb Callback
+			loadCallback("message-with-email-quick-reply.json", &cb)
+
+			quickReply := cb.Entries[0].Messaging[0].Message.QuickReply
+
+			email, ok := quickReply.Email()
+			Expect(ok).To(BeTrue())
+			Expect(email).To(Equal("user@example.com"))
+
+			_, ok = quickReply.PhoneNumber()
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should not treat a developer defined payload as a phone number or email", func() {
+			quickReply := &CallbackQuickReply{Payload: "DEVELOPER_DEFINED_PAYLOAD"}
+
+			_, ok := quickReply.PhoneNumber()
+			Expect(ok).To(BeFalse())
+
+			_, ok = quickReply.Email()
+			Expect(ok).To(BeFalse())
+
+			for _, payload := range []string{"42", "1234567", "20240101", "2024-01-01", "+1 650-555-1234", "+0123456789"} {
+				_, ok = (&CallbackQuickReply{Payload: payload}).PhoneNumber()
+				Expect(ok).To(BeFalse(), payload)
+			}
+
+			for _, payload := range []string{"foo@bar", "foo@bar.", "Foo <foo@bar.com>", "not an email"} {
+				_, ok = (&CallbackQuickReply{Payload: payload}).Email()
+				Expect(ok).To(BeFalse(), payload)
+			}
+		})
+
 		It("should unmarshal a callback with a message with an image attachment", func() {
 			var cb Callback
 			loadCallback("message-with-image-attachment.json", &cb)
@@ -63,6 +111,15 @@ var _ = Describe("Callback Models", func() {
 		})
 	})
 
+	Describe("Read Model", func() {
+		It("should unmarshal a read callback", func() {
+			var cb Callback
+			loadCallback("read.json", &cb)
+			Expect(cb.Entries[0].Messaging[0].Read.Watermark).To(Equal(1458668856253))
+			Expect(cb.Entries[0].Messaging[0].Read.Sequence).To(Equal(38))
+		})
+	})
+
 	Describe("Postback Model", func() {
 		It("should unmarshal a postback callback", func() {
 			var cb Callback
@@ -109,12 +166,48 @@ var _ = Describe("Send API Models", func() {
 		expectCorrectMarshaling(sendRequest, "text-message-with-location-quick-reply.json")
 	})
 
+	It("should marshal a send request with phone number and email quick replies", func() {
+		sendRequest := TextMessage("How can we reach you?").WithQuickReplies(PhoneNumberReply(), EmailReply()).To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "text-message-with-contact-quick-replies.json")
+	})
+
 	It("should marshal a send request with an image attached using the URL of the image", func() {
 		sendRequest := ImageMessage("IMAGE_URL").To("USER_ID")
 
 		expectCorrectMarshaling(sendRequest, "message-with-image-attachment.json")
 	})
 
+	It("should marshal a send request with an audio file attached using the URL of the file", func() {
+		sendRequest := AudioMessage("AUDIO_URL").To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-audio-attachment.json")
+	})
+
+	It("should marshal a send request with a video attached using the URL of the video", func() {
+		sendRequest := VideoMessage("VIDEO_URL").To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-video-attachment.json")
+	})
+
+	It("should marshal a send request with a file attached using the URL of the file", func() {
+		sendRequest := FileMessage("FILE_URL").To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-file-attachment.json")
+	})
+
+	It("should marshal a send request with a reusable image attachment", func() {
+		sendRequest := ImageMessage("IMAGE_URL").Reusable().To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-reusable-image-attachment.json")
+	})
+
+	It("should marshal a send request with a previously uploaded attachment", func() {
+		sendRequest := AttachmentIdMessage("image", "1857777774821032").To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-attachment-id.json")
+	})
+
 	It("should marshal an a message with an image attached by uploading the image", func() {
 		imageBytes, err := ioutil.ReadFile("./sample-send-api-data/fb-logo.png")
 		if err != nil {
@@ -135,6 +228,35 @@ var _ = Describe("Send API Models", func() {
 		expectCorrectMarshaling(sendRequest, "message-with-button-attachment.json")
 	})
 
+	It("should marshal a send request with call, webview and login buttons", func() {
+		sendRequest := ButtonTemplateMessage("Need help?",
+			CallButton("Call Representative", "+15105551234"),
+			URLButton("Select Criteria", "https://petersfancyapparel.com/criteria_selector").
+				WithWebviewHeightRatio("tall").
+				WithMessengerExtensions("https://petersfancyapparel.com/fallback").
+				HideWebviewShareButton(),
+			LoginButton("https://www.example.com/authorize")).
+			To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-button-types-attachment.json")
+	})
+
+	It("should marshal a send request with share and logout buttons", func() {
+		quizResult := &GenericElement{
+			Title:    "I took Peter's 'Which Hat Are You?' Quiz",
+			ImageURL: "https://bot.peters-hats.com/img/hats/fez.jpg",
+			Subtitle: "My result: Fez",
+			Buttons:  []*Button{URLButton("Take Quiz", "https://bot.peters-hats.com/hatquiz")},
+		}
+
+		sendRequest := ButtonTemplateMessage("Tell your friends!",
+			ShareButton().WithShareContents(GenericTemplateMessage(quizResult)),
+			LogoutButton()).
+			To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-share-button-attachment.json")
+	})
+
 	It("should marshal a send request with a generic attachment", func() {
 		viewWebsite := URLButton("View Website", "https://petersapparel.parseapp.com/view_item?item_id=100")
 
@@ -152,6 +274,36 @@ var _ = Describe("Send API Models", func() {
 		expectCorrectMarshaling(sendRequest, "message-with-generic-template-attachment.json")
 	})
 
+	It("should marshal a send request with a generic attachment with tappable elements", func() {
+		welcome := NewGenericElement("Welcome to Peter's Hats").
+			WithImageURL("http://petersapparel.parseapp.com/img/item100-thumb.png").
+			WithSubtitle("We've got the right hat for everyone.").
+			WithDefaultAction(URLDefaultAction("https://petersapparel.parseapp.com/view_item?item_id=100"))
+
+		grayHat := NewGenericElement("Classic Gray Hat").
+			WithItemURL("https://petersapparel.parseapp.com/view_item?item_id=101")
+
+		sendRequest := GenericTemplateMessage(welcome, grayHat).
+			WithImageAspectRatio("square").
+			Sharable().
+			To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-tappable-generic-template-attachment.json")
+	})
+
+	It("should leave messages without a generic payload unchanged when setting generic options", func() {
+		buttonRequest := ButtonTemplateMessage("What do you want to do next?", PostbackButton("Start Chatting", "USER_DEFINED_PAYLOAD"))
+		payload := buttonRequest.Message.Attachment.Payload
+
+		buttonRequest.WithImageAspectRatio("square").Sharable()
+
+		Expect(buttonRequest.Message.Attachment.Payload).To(Equal(payload))
+
+		textRequest := TextMessage("Hello, world!").WithImageAspectRatio("square").Sharable()
+
+		Expect(textRequest.Message.Attachment).To(BeNil())
+	})
+
 	It("should marshal a send request with a receipt attachment", func() {
 		header := &ReceiptHeader{
 			RecipientName: "Stephane Crozatier",
@@ -213,6 +365,63 @@ var _ = Describe("Send API Models", func() {
 		expectCorrectMarshaling(sendRequest, "message-with-receipt-attachment.json")
 	})
 
+	Describe("List Template", func() {
+		It("should marshal a send request with a list attachment", func() {
+			collection := NewListElement("Classic T-Shirt Collection").
+				WithSubtitle("See all our colors").
+				WithImageURL("https://peterssendreceiveapp.ngrok.io/img/collection.png").
+				WithButton(URLButton("View", "https://peterssendreceiveapp.ngrok.io/collection"))
+
+			whiteShirt := NewListElement("Classic White T-Shirt").
+				WithSubtitle("See all our colors").
+				WithDefaultAction(URLDefaultAction("https://peterssendreceiveapp.ngrok.io/view?item=100"))
+
+			sendRequest := ListTemplateMessage(collection, whiteShirt).
+				WithListTopElementStyle("compact").
+				WithListButton(PostbackButton("View More", "VIEW_MORE_PAYLOAD")).
+				To("USER_ID")
+
+			expectCorrectMarshaling(sendRequest, "message-with-list-template-attachment.json")
+		})
+
+		It("should leave messages without a list payload unchanged when setting list options", func() {
+			buttonRequest := ButtonTemplateMessage("What do you want to do next?", PostbackButton("Start Chatting", "USER_DEFINED_PAYLOAD"))
+			payload := buttonRequest.Message.Attachment.Payload
+
+			buttonRequest.WithListTopElementStyle("compact").WithListButton(PostbackButton("View More", "VIEW_MORE_PAYLOAD"))
+
+			Expect(buttonRequest.Message.Attachment.Payload).To(Equal(payload))
+
+			textRequest := TextMessage("Hello, world!").
+				WithListTopElementStyle("compact").
+				WithListButton(PostbackButton("View More", "VIEW_MORE_PAYLOAD"))
+
+			Expect(textRequest.Message.Attachment).To(BeNil())
+		})
+
+		It("should validate the number of elements", func() {
+			element := NewListElement("Classic White T-Shirt")
+
+			payload := func(sendRequest *SendRequest) *ListPayload {
+				return sendRequest.Message.Attachment.Payload.(*ListPayload)
+			}
+
+			Expect(payload(ListTemplateMessage(element)).Validate()).ToNot(Succeed())
+			Expect(payload(ListTemplateMessage(element, element)).Validate()).To(Succeed())
+			Expect(payload(ListTemplateMessage(element, element, element, element)).Validate()).To(Succeed())
+			Expect(payload(ListTemplateMessage(element, element, element, element, element)).Validate()).ToNot(Succeed())
+		})
+	})
+
+	It("should marshal a send request with a media attachment", func() {
+		element := MediaAttachmentElement("image", "1857777774821032").
+			WithButtons(URLButton("View Website", "https://petersapparel.parseapp.com"))
+
+		sendRequest := MediaTemplateMessage(element).To("USER_ID")
+
+		expectCorrectMarshaling(sendRequest, "message-with-media-template-attachment.json")
+	})
+
 	It("should marshal a send request to a phone number", func() {
 		sendRequest := TextMessage("Hello, world!").ToPhoneNumber("+1(212)555-2368")
 
@@ -237,6 +446,30 @@ var _ = Describe("Send API Models", func() {
 		expectCorrectMarshaling(sendRequest, "text-message-no-push.json")
 	})
 
+	It("should marshal a send request with a RESPONSE messaging type", func() {
+		sendRequest := TextMessage("Hello, world!").To("USER_ID").AsResponse()
+
+		expectCorrectMarshaling(sendRequest, "text-message-response.json")
+	})
+
+	It("should marshal a send request with an UPDATE messaging type", func() {
+		sendRequest := TextMessage("Hello, world!").To("USER_ID").AsUpdate()
+
+		expectCorrectMarshaling(sendRequest, "text-message-update.json")
+	})
+
+	It("should marshal a send request with a message tag", func() {
+		sendRequest := TextMessage("Your event starts in one hour.").To("USER_ID").WithTag(ConfirmedEventUpdate)
+
+		expectCorrectMarshaling(sendRequest, "text-message-with-tag.json")
+	})
+
+	It("should marshal a sender action request", func() {
+		senderActionRequest := TypingOn().To("USER_ID")
+
+		expectCorrectMarshaling(senderActionRequest, "sender-action-typing-on.json")
+	})
+
 	It("should unmarshal a successful response", func() {
 		var response SendResponse
 		loadSendResponse("successful-response.json", &response)
@@ -254,6 +487,36 @@ var _ = Describe("Send API Models", func() {
 	})
 })
 
+var _ = Describe("Messenger Profile Models", func() {
+	It("should marshal a messenger profile", func() {
+		myAccount := NestedMenuItem("My Account",
+			PostbackMenuItem("Pay Bill", "PAYBILL_PAYLOAD"),
+			PostbackMenuItem("History", "HISTORY_PAYLOAD"))
+
+		profile := NewMessengerProfile().
+			WithGetStarted("GET_STARTED_PAYLOAD").
+			WithGreeting(
+				GreetingText("Hello, {{user_first_name}}!"),
+				LocalizedGreetingText("es_LA", "¡Hola, {{user_first_name}}!")).
+			WithPersistentMenu(
+				DefaultPersistentMenu(myAccount, URLMenuItem("Latest News", "http://petershats.parseapp.com/hat-news")).
+					DisableComposerInput()).
+			WithWhitelistedDomains("https://petersfancyapparel.com")
+
+		profileBytes, err := json.Marshal(profile)
+		if err != nil {
+			Fail(fmt.Sprintf("Error marshaling value: %v", err))
+		}
+
+		fileBytes, err := ioutil.ReadFile("./sample-messenger-profile-data/messenger-profile.json")
+		if err != nil {
+			Fail(fmt.Sprintf("Error reading file: %v", err))
+		}
+
+		Expect(profileBytes).To(MatchJSON(fileBytes))
+	})
+})
+
 func loadCallback(fileName string, cb *Callback) {
 	fileBytes, err := ioutil.ReadFile("./sample-callback-data/" + fileName)
 	if err != nil {
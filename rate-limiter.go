@@ -0,0 +1,143 @@
+package fbmessenger
+
+import (
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+const defaultThrottleBackoff = time.Second
+
+/*
+RateLimiter is a token bucket rate limiter with a separate bucket for each page access
+token, so that one busy page does not starve the others. Set it on a Client to have
+SendWithContext block until sending is allowed, or the context is done.
+
+	client := fbmessenger.Client{
+		RateLimiter: fbmessenger.NewRateLimiter(10, 20),
+	}
+
+A Rate of 0 or less puts no limit on how fast a page sends, so the zero value only pauses
+pages that are throttled.
+
+When Facebook responds that a page is sending too fast (see IsRateLimited), the Client
+calls Throttled, which stops sending for that page for ThrottleBackoff (default 1s) and
+calls OnThrottled if it is set.
+*/
+type RateLimiter struct {
+	Rate            float64
+	Burst           int
+	ThrottleBackoff time.Duration
+	OnThrottled     func(pageAccessToken string, err error)
+
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens      float64
+	lastRefill  time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a RateLimiter that allows rate sends per second for each page,
+// with bursts of up to burst sends. Sends are not limited if rate is 0 or less.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		Rate:  rate,
+		Burst: burst,
+	}
+}
+
+// Wait blocks until a send is allowed for the page access token, or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, pageAccessToken string) error {
+	for {
+		wait := l.reserve(pageAccessToken)
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Throttled stops sends for the page access token for ThrottleBackoff and calls OnThrottled.
+func (l *RateLimiter) Throttled(pageAccessToken string, err error) {
+	backoff := l.ThrottleBackoff
+	if backoff <= 0 {
+		backoff = defaultThrottleBackoff
+	}
+
+	l.mutex.Lock()
+	bucket := l.bucket(pageAccessToken, time.Now())
+	bucket.pausedUntil = time.Now().Add(backoff)
+	bucket.tokens = 0
+	l.mutex.Unlock()
+
+	if l.OnThrottled != nil {
+		l.OnThrottled(pageAccessToken, err)
+	}
+}
+
+// reserve takes a token from the bucket for the page access token if one is available,
+// and otherwise returns how long to wait before trying again.
+func (l *RateLimiter) reserve(pageAccessToken string) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	bucket := l.bucket(pageAccessToken, now)
+
+	if now.Before(bucket.pausedUntil) {
+		return bucket.pausedUntil.Sub(now)
+	}
+
+	if l.Rate <= 0 {
+		return 0
+	}
+
+	bucket.tokens += now.Sub(bucket.lastRefill).Seconds() * l.Rate
+	if bucket.tokens > float64(l.burst()) {
+		bucket.tokens = float64(l.burst())
+	}
+	bucket.lastRefill = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - bucket.tokens) / l.Rate * float64(time.Second))
+}
+
+func (l *RateLimiter) bucket(pageAccessToken string, now time.Time) *tokenBucket {
+	if l.buckets == nil {
+		l.buckets = make(map[string]*tokenBucket)
+	}
+
+	bucket, ok := l.buckets[pageAccessToken]
+	if !ok {
+		bucket = &tokenBucket{
+			tokens:     float64(l.burst()),
+			lastRefill: now,
+		}
+		l.buckets[pageAccessToken] = bucket
+	}
+
+	return bucket
+}
+
+func (l *RateLimiter) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+
+	return l.Burst
+}
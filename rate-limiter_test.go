@@ -0,0 +1,76 @@
+package fbmessenger_test
+
+import (
+	. "github.com/ekyoung/fbmessenger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/net/context"
+	"time"
+)
+
+var _ = Describe("RateLimiter", func() {
+	var limiter *RateLimiter
+
+	BeforeEach(func() {
+		limiter = NewRateLimiter(1, 2)
+	})
+
+	It("should allow a burst of sends without waiting", func() {
+		start := time.Now()
+
+		Expect(limiter.Wait(context.Background(), "PAGE_1")).To(Succeed())
+		Expect(limiter.Wait(context.Background(), "PAGE_1")).To(Succeed())
+
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("should block once the burst is used until the context is done", func() {
+		limiter.Wait(context.Background(), "PAGE_1")
+		limiter.Wait(context.Background(), "PAGE_1")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		Expect(limiter.Wait(ctx, "PAGE_1")).To(Equal(context.DeadlineExceeded))
+	})
+
+	It("should keep a separate bucket for each page access token", func() {
+		limiter.Wait(context.Background(), "PAGE_1")
+		limiter.Wait(context.Background(), "PAGE_1")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		Expect(limiter.Wait(ctx, "PAGE_2")).To(Succeed())
+	})
+
+	It("should not limit sends when the rate is not set", func() {
+		for _, limiter := range []*RateLimiter{&RateLimiter{}, NewRateLimiter(0, 2)} {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+
+			for i := 0; i < 5; i++ {
+				Expect(limiter.Wait(ctx, "PAGE_1")).To(Succeed())
+			}
+
+			cancel()
+		}
+	})
+
+	It("should pause sends and call the hook when throttled", func() {
+		var throttledToken string
+		limiter.ThrottleBackoff = time.Minute
+		limiter.OnThrottled = func(pageAccessToken string, err error) {
+			throttledToken = pageAccessToken
+		}
+
+		limiter.Throttled("PAGE_1", &SendError{Code: 613})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		Expect(throttledToken).To(Equal("PAGE_1"))
+		Expect(limiter.Wait(ctx, "PAGE_1")).To(Equal(context.DeadlineExceeded))
+	})
+})
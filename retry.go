@@ -0,0 +1,139 @@
+package fbmessenger
+
+import (
+	"errors"
+	"golang.org/x/net/context"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+	defaultMultiplier     = 2.0
+)
+
+/*
+RetryPolicy configures how a Client retries requests that fail with a transient error.
+
+	client := fbmessenger.Client{
+		RetryPolicy: &fbmessenger.RetryPolicy{
+			MaxAttempts: 3,
+			Jitter:      0.5,
+		},
+	}
+
+MaxAttempts is the total number of attempts, including the first. The wait before each
+retry starts at InitialBackoff (default 100ms) and is multiplied by Multiplier (default 2)
+after each attempt, up to MaxBackoff (default 5s). Jitter, between 0 and 1, is the fraction
+of each wait that is randomized. Retryable decides which errors are retried, and defaults
+to IsRetryable.
+
+A retry is never started if the context is done, or if its deadline would pass while waiting.
+*/
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	Retryable      func(err error) bool
+}
+
+/*
+IsRetryable returns true if err is likely to be transient: a temporary error returned
+from Facebook, a response with a 5xx status code, or a network error such as a timeout
+or reset connection.
+*/
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if IsTemporary(err) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return IsRetryable(err)
+}
+
+// backoff returns how long to wait before the given retry, starting at 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.InitialBackoff
+	if wait <= 0 {
+		wait = defaultInitialBackoff
+	}
+
+	maxWait := p.MaxBackoff
+	if maxWait <= 0 {
+		maxWait = defaultMaxBackoff
+	}
+
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultMultiplier
+	}
+
+	for i := 1; i < retry && wait < maxWait; i++ {
+		wait = time.Duration(float64(wait) * multiplier)
+	}
+
+	if wait > maxWait {
+		wait = maxWait
+	}
+
+	if p.Jitter > 0 {
+		wait -= time.Duration(rand.Float64() * p.Jitter * float64(wait))
+	}
+
+	return wait
+}
+
+// wait sleeps before the given retry. It returns false if the retry should not be made
+// because the context is done or its deadline would pass first.
+func (p *RetryPolicy) wait(ctx context.Context, retry int) bool {
+	wait := p.backoff(retry)
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
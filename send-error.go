@@ -0,0 +1,150 @@
+package fbmessenger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+func (e *SendError) Error() string {
+	if e.ErrorSubcode != 0 {
+		return fmt.Sprintf("facebook error %v (subcode %v): %v", e.Code, e.ErrorSubcode, e.Message)
+	}
+
+	return fmt.Sprintf("facebook error %v: %v", e.Code, e.Message)
+}
+
+/*
+IsRateLimited returns true if err is, or wraps, a *SendError indicating too many
+requests have been made.
+
+See https://developers.facebook.com/docs/messenger-platform/send-api-reference/errors
+*/
+func IsRateLimited(err error) bool {
+	sendErr, ok := asSendError(err)
+	if !ok {
+		return false
+	}
+
+	switch sendErr.Code {
+	case 4, 17, 32, 613:
+		return true
+	}
+
+	return false
+}
+
+// IsUserBlocked returns true if err is, or wraps, a *SendError indicating the user has
+// blocked the page or can otherwise not receive messages.
+func IsUserBlocked(err error) bool {
+	sendErr, ok := asSendError(err)
+	if !ok {
+		return false
+	}
+
+	return sendErr.Code == 551 || sendErr.ErrorSubcode == 1545041 || sendErr.ErrorSubcode == 2018108
+}
+
+// IsOutsideMessagingWindow returns true if err is, or wraps, a *SendError indicating the
+// message was sent outside of the allowed messaging window.
+func IsOutsideMessagingWindow(err error) bool {
+	sendErr, ok := asSendError(err)
+	if !ok {
+		return false
+	}
+
+	return sendErr.ErrorSubcode == 2018065 || sendErr.ErrorSubcode == 2018278
+}
+
+// IsInvalidToken returns true if err is, or wraps, a *SendError indicating the page access
+// token is invalid or expired.
+func IsInvalidToken(err error) bool {
+	sendErr, ok := asSendError(err)
+	if !ok {
+		return false
+	}
+
+	return sendErr.Code == 190
+}
+
+// IsTemporary returns true if err is, or wraps, a *SendError indicating a temporary failure
+// on the part of Facebook. The request may succeed if tried again later.
+func IsTemporary(err error) bool {
+	sendErr, ok := asSendError(err)
+	if !ok {
+		return false
+	}
+
+	switch sendErr.Code {
+	case 1, 2, 1200:
+		return true
+	}
+
+	return sendErr.IsTransient
+}
+
+func asSendError(err error) (*SendError, bool) {
+	var sendErr *SendError
+	ok := errors.As(err, &sendErr)
+
+	return sendErr, ok
+}
+
+// maxErrorBodyLength limits how much of an unexpected response body is kept in an APIError.
+const maxErrorBodyLength = 512
+
+/*
+APIError is returned when Facebook responds with a status code other than 2xx. When the
+body of the response is a Graph API error, SendError holds the decoded error and the
+helpers like IsRateLimited can be used to classify it.
+*/
+type APIError struct {
+	StatusCode int
+	Body       string
+	FBTraceId  string
+	SendError  *SendError
+}
+
+func (e *APIError) Error() string {
+	if e.SendError != nil {
+		return fmt.Sprintf("status %v: %v", e.StatusCode, e.SendError.Error())
+	}
+
+	return fmt.Sprintf("status %v: %v", e.StatusCode, e.Body)
+}
+
+// Unwrap returns the decoded Graph API error, if any.
+func (e *APIError) Unwrap() error {
+	if e.SendError == nil {
+		return nil
+	}
+
+	return e.SendError
+}
+
+type errorEnvelope struct {
+	Error *SendError `json:"error"`
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		FBTraceId:  resp.Header.Get("X-FB-Trace-ID"),
+	}
+
+	envelope := &errorEnvelope{}
+	if json.Unmarshal(body, envelope) == nil && envelope.Error != nil {
+		apiErr.SendError = envelope.Error
+		if envelope.Error.FBTraceId != "" {
+			apiErr.FBTraceId = envelope.Error.FBTraceId
+		}
+	}
+
+	if len(body) > maxErrorBodyLength {
+		body = body[:maxErrorBodyLength]
+	}
+	apiErr.Body = string(body)
+
+	return apiErr
+}
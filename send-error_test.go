@@ -0,0 +1,62 @@
+package fbmessenger_test
+
+import (
+	. "github.com/ekyoung/fbmessenger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"errors"
+	"fmt"
+)
+
+var _ = Describe("SendError", func() {
+	It("should implement error", func() {
+		var err error = &SendError{Code: 100, Message: "Invalid parameter"}
+
+		Expect(err.Error()).To(Equal("facebook error 100: Invalid parameter"))
+	})
+
+	It("should classify rate limit errors", func() {
+		Expect(IsRateLimited(&SendError{Code: 613})).To(BeTrue())
+		Expect(IsRateLimited(&SendError{Code: 4, ErrorSubcode: 2018022})).To(BeTrue())
+		Expect(IsRateLimited(&SendError{Code: 100})).To(BeFalse())
+	})
+
+	It("should classify user blocked errors", func() {
+		Expect(IsUserBlocked(&SendError{Code: 551, ErrorSubcode: 1545041})).To(BeTrue())
+		Expect(IsUserBlocked(&SendError{Code: 10, ErrorSubcode: 2018108})).To(BeTrue())
+		Expect(IsUserBlocked(&SendError{Code: 100})).To(BeFalse())
+	})
+
+	It("should classify messaging window errors", func() {
+		Expect(IsOutsideMessagingWindow(&SendError{Code: 10, ErrorSubcode: 2018278})).To(BeTrue())
+		Expect(IsOutsideMessagingWindow(&SendError{Code: 10})).To(BeFalse())
+	})
+
+	It("should classify invalid token errors", func() {
+		Expect(IsInvalidToken(&SendError{Code: 190})).To(BeTrue())
+		Expect(IsInvalidToken(&SendError{Code: 100})).To(BeFalse())
+	})
+
+	It("should classify temporary errors", func() {
+		Expect(IsTemporary(&SendError{Code: 2})).To(BeTrue())
+		Expect(IsTemporary(&SendError{Code: 1200})).To(BeTrue())
+		Expect(IsTemporary(&SendError{Code: 100, IsTransient: true})).To(BeTrue())
+		Expect(IsTemporary(&SendError{Code: 100})).To(BeFalse())
+	})
+
+	It("should classify wrapped errors", func() {
+		err := fmt.Errorf("sending welcome message: %w", &SendError{Code: 613})
+
+		Expect(IsRateLimited(err)).To(BeTrue())
+	})
+
+	It("should not classify other errors", func() {
+		err := errors.New("connection refused")
+
+		Expect(IsRateLimited(err)).To(BeFalse())
+		Expect(IsTemporary(err)).To(BeFalse())
+		Expect(IsTemporary(nil)).To(BeFalse())
+	})
+})
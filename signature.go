@@ -0,0 +1,171 @@
+package fbmessenger
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// SignatureHeader is the header Facebook uses to send the HMAC-SHA1 signature of a callback.
+	SignatureHeader = "X-Hub-Signature"
+
+	// SignatureSHA256Header is the header Facebook uses to send the HMAC-SHA256 signature of a callback.
+	SignatureSHA256Header = "X-Hub-Signature-256"
+)
+
+// ErrMissingAppSecret is returned when a signature is verified with an empty app secret,
+// since anyone could sign a payload with it.
+var ErrMissingAppSecret = errors.New("app secret is required to verify signatures")
+
+// MissingSignatureError is returned when a callback does not include a signature header.
+type MissingSignatureError struct {
+	Header string
+}
+
+func (e *MissingSignatureError) Error() string {
+	return fmt.Sprintf("missing signature header %v", e.Header)
+}
+
+// SignatureMismatchError is returned when the signature of a callback does not match the
+// signature computed using the app secret.
+type SignatureMismatchError struct {
+	Header string
+}
+
+func (e *SignatureMismatchError) Error() string {
+	return fmt.Sprintf("signature in header %v does not match payload", e.Header)
+}
+
+/*
+VerifySHA1Signature checks that signature, the value of the X-Hub-Signature header
+(e.g. "sha1=..."), is the HMAC-SHA1 of payload using your app secret.
+
+See https://developers.facebook.com/docs/messenger-platform/webhook-reference#security
+*/
+func VerifySHA1Signature(payload []byte, signature, appSecret string) error {
+	return verifySignature(SignatureHeader, "sha1=", sha1.New, payload, signature, appSecret)
+}
+
+// VerifySHA256Signature checks that signature, the value of the X-Hub-Signature-256 header
+// (e.g. "sha256=..."), is the HMAC-SHA256 of payload using your app secret.
+func VerifySHA256Signature(payload []byte, signature, appSecret string) error {
+	return verifySignature(SignatureSHA256Header, "sha256=", sha256.New, payload, signature, appSecret)
+}
+
+/*
+VerifySignature checks the signature headers of a callback against payload, the raw bytes
+of the request body. The X-Hub-Signature-256 header is preferred when it is present,
+otherwise the X-Hub-Signature header is used. A *MissingSignatureError is returned
+if neither header is present and a *SignatureMismatchError if the signature is wrong.
+ErrMissingAppSecret is returned if appSecret is empty.
+*/
+func VerifySignature(header http.Header, payload []byte, appSecret string) error {
+	if appSecret == "" {
+		return ErrMissingAppSecret
+	}
+
+	if signature := header.Get(SignatureSHA256Header); signature != "" {
+		return VerifySHA256Signature(payload, signature, appSecret)
+	}
+
+	if signature := header.Get(SignatureHeader); signature != "" {
+		return VerifySHA1Signature(payload, signature, appSecret)
+	}
+
+	return &MissingSignatureError{Header: SignatureSHA256Header}
+}
+
+func verifySignature(headerName, prefix string, newHash func() hash.Hash, payload []byte, signature, appSecret string) error {
+	if appSecret == "" {
+		return ErrMissingAppSecret
+	}
+
+	if signature == "" {
+		return &MissingSignatureError{Header: headerName}
+	}
+
+	if !strings.HasPrefix(signature, prefix) {
+		return &SignatureMismatchError{Header: headerName}
+	}
+
+	actual, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return &SignatureMismatchError{Header: headerName}
+	}
+
+	mac := hmac.New(newHash, []byte(appSecret))
+	mac.Write(payload)
+
+	if !hmac.Equal(actual, mac.Sum(nil)) {
+		return &SignatureMismatchError{Header: headerName}
+	}
+
+	return nil
+}
+
+/*
+SignatureVerifier is middleware that verifies the signature of each request before
+passing it on to the next handler. Requests without a valid signature are rejected with
+status code 403 (Forbidden).
+
+	handler := fbmessenger.SignatureVerifier("YOUR_APP_SECRET", &fbmessenger.WebhookHandler{
+		VerifyToken: "YOUR_VERIFY_TOKEN",
+		Dispatcher:  dispatcher,
+	})
+
+Verification requests (GET) carry no payload and are passed on without checking. Requests
+with a body larger than DefaultMaxBodyBytes are rejected with status code 413 (Request
+Entity Too Large) before reaching next, whatever the MaxBodyBytes of a WebhookHandler
+behind it. Use SignatureVerifierWithLimit to allow larger bodies. SignatureVerifier panics
+if appSecret is empty.
+*/
+func SignatureVerifier(appSecret string, next http.Handler) http.Handler {
+	return SignatureVerifierWithLimit(appSecret, DefaultMaxBodyBytes, next)
+}
+
+// SignatureVerifierWithLimit is like SignatureVerifier but rejects requests with a body
+// larger than limit bytes, which defaults to DefaultMaxBodyBytes when it is less than 1.
+// Set it to match the MaxBodyBytes of the WebhookHandler passed as next.
+func SignatureVerifierWithLimit(appSecret string, limit int64, next http.Handler) http.Handler {
+	if appSecret == "" {
+		panic(ErrMissingAppSecret)
+	}
+
+	limit = maxBodyBytes(limit)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+		r.Body.Close()
+		if isBodyTooLarge(err) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = VerifySignature(r.Header, body, appSecret)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,173 @@
+package fbmessenger_test
+
+import (
+	. "github.com/ekyoung/fbmessenger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+var _ = Describe("Signature Verification", func() {
+	const appSecret = "APP_SECRET"
+
+	payload := []byte(`{"object":"page","entry":[]}`)
+
+	sign := func(newHash func() hash.Hash, prefix string, body []byte) string {
+		mac := hmac.New(newHash, []byte(appSecret))
+		mac.Write(body)
+		return prefix + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	Describe("VerifySHA1Signature", func() {
+		It("should accept a valid signature", func() {
+			Expect(VerifySHA1Signature(payload, sign(sha1.New, "sha1=", payload), appSecret)).To(Succeed())
+		})
+
+		It("should reject a signature computed with a different secret", func() {
+			err := VerifySHA1Signature(payload, sign(sha1.New, "sha1=", payload), "OTHER_SECRET")
+
+			Expect(err).To(BeAssignableToTypeOf(&SignatureMismatchError{}))
+		})
+
+		It("should reject a missing signature", func() {
+			err := VerifySHA1Signature(payload, "", appSecret)
+
+			Expect(err).To(BeAssignableToTypeOf(&MissingSignatureError{}))
+		})
+	})
+
+	Describe("VerifySHA256Signature", func() {
+		It("should accept a valid signature", func() {
+			Expect(VerifySHA256Signature(payload, sign(sha256.New, "sha256=", payload), appSecret)).To(Succeed())
+		})
+
+		It("should reject a signature with the wrong prefix", func() {
+			err := VerifySHA256Signature(payload, sign(sha256.New, "sha1=", payload), appSecret)
+
+			Expect(err).To(BeAssignableToTypeOf(&SignatureMismatchError{}))
+		})
+	})
+
+	Describe("VerifySignature", func() {
+		It("should prefer the SHA256 header", func() {
+			header := http.Header{}
+			header.Set(SignatureSHA256Header, sign(sha256.New, "sha256=", payload))
+			header.Set(SignatureHeader, "sha1=bogus")
+
+			Expect(VerifySignature(header, payload, appSecret)).To(Succeed())
+		})
+
+		It("should fall back to the SHA1 header", func() {
+			header := http.Header{}
+			header.Set(SignatureHeader, sign(sha1.New, "sha1=", payload))
+
+			Expect(VerifySignature(header, payload, appSecret)).To(Succeed())
+		})
+
+		It("should return a missing signature error when there are no headers", func() {
+			err := VerifySignature(http.Header{}, payload, appSecret)
+
+			Expect(err).To(BeAssignableToTypeOf(&MissingSignatureError{}))
+		})
+
+		It("should reject an empty app secret", func() {
+			header := http.Header{}
+			header.Set(SignatureSHA256Header, sign(sha256.New, "sha256=", payload))
+
+			Expect(VerifySignature(header, payload, "")).To(Equal(ErrMissingAppSecret))
+		})
+	})
+
+	Describe("SignatureVerifier", func() {
+		var (
+			nextCalls int
+			nextBody  string
+
+			handler http.Handler
+		)
+
+		BeforeEach(func() {
+			nextCalls = 0
+			nextBody = ""
+
+			handler = SignatureVerifier(appSecret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalls++
+				body, _ := ioutil.ReadAll(r.Body)
+				nextBody = string(body)
+			}))
+		})
+
+		It("should pass signed requests on with the body intact", func() {
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader(string(payload)))
+			req.Header.Set(SignatureSHA256Header, sign(sha256.New, "sha256=", payload))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(nextCalls).To(Equal(1))
+			Expect(nextBody).To(Equal(string(payload)))
+		})
+
+		It("should reject unsigned requests", func() {
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader(string(payload)))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(nextCalls).To(Equal(0))
+		})
+
+		It("should reject requests with a body over the limit", func() {
+			largePayload := []byte(strings.Repeat("a", DefaultMaxBodyBytes+1))
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader(string(largePayload)))
+			req.Header.Set(SignatureSHA256Header, sign(sha256.New, "sha256=", largePayload))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			Expect(recorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			Expect(nextCalls).To(Equal(0))
+		})
+
+		It("should accept bodies up to a larger limit", func() {
+			largePayload := []byte(strings.Repeat("a", DefaultMaxBodyBytes+1))
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader(string(largePayload)))
+			req.Header.Set(SignatureSHA256Header, sign(sha256.New, "sha256=", largePayload))
+			recorder := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalls++
+			})
+
+			SignatureVerifierWithLimit(appSecret, 2*DefaultMaxBodyBytes, next).ServeHTTP(recorder, req)
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(nextCalls).To(Equal(1))
+		})
+
+		It("should panic when the app secret is empty", func() {
+			Expect(func() { SignatureVerifier("", handler) }).To(Panic())
+		})
+
+		It("should pass verification requests on without a signature", func() {
+			req := httptest.NewRequest("GET", "/webhook?hub.mode=subscribe", nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			Expect(nextCalls).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package fbmessenger
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+SplitText splits text into chunks of at most maxLength characters, which defaults to
+MaxTextLength when it is less than 1. Each chunk ends at the last sentence boundary that
+fits, or else the last word boundary, or else it is cut between two UTF-8 runes. Whitespace
+at the start and end of each chunk where the text is split is removed, and chunks with
+nothing but whitespace are dropped, so no chunks are returned for text that is blank.
+*/
+func SplitText(text string, maxLength int) []string {
+	if maxLength < 1 {
+		maxLength = MaxTextLength
+	}
+
+	var chunks []string
+	for utf8.RuneCountInString(text) > maxLength {
+		split := splitPoint(text, maxLength)
+
+		chunks = appendChunk(chunks, strings.TrimRightFunc(text[:split], unicode.IsSpace))
+		text = strings.TrimLeftFunc(text[split:], unicode.IsSpace)
+	}
+
+	return appendChunk(chunks, text)
+}
+
+// appendChunk appends chunk to chunks unless it is blank.
+func appendChunk(chunks []string, chunk string) []string {
+	if strings.TrimSpace(chunk) == "" {
+		return chunks
+	}
+
+	return append(chunks, chunk)
+}
+
+// splitPoint returns the byte index at which to end a chunk of text with at most maxLength
+// runes, preferring whitespace after a sentence, then any whitespace, then the last rune.
+func splitPoint(text string, maxLength int) int {
+	sentence, word, runeCut := 0, 0, 0
+
+	var previous rune
+	count, seenText := 0, false
+
+	for i, r := range text {
+		if count > maxLength {
+			break
+		}
+
+		if count == maxLength {
+			runeCut = i
+		}
+
+		if unicode.IsSpace(r) {
+			if seenText {
+				word = i
+
+				if r == '\n' || previous == '.' || previous == '!' || previous == '?' {
+					sentence = i
+				}
+			}
+		} else {
+			seenText = true
+		}
+
+		previous = r
+		count++
+	}
+
+	switch {
+	case sentence > 0:
+		return sentence
+	case word > 0:
+		return word
+	default:
+		return runeCut
+	}
+}
+
+// SendRequests is a sequence of requests to be sent in order, such as the chunks of a long
+// text message. Send them with Client.SendAll.
+type SendRequests []*SendRequest
+
+/*
+SplitTextMessage is a fluent helper method for creating a sequence of text messages from
+text that may be longer than MaxTextLength. The text is split with SplitText, and each chunk
+becomes one message. No messages are created for text that is blank.
+
+	requests := fbmessenger.SplitTextMessage(longText).
+		WithQuickReplies(fbmessenger.TextReply("Tell me more", "MORE")).
+		To("USER_ID")
+
+	responses, err := client.SendAll(requests, "YOUR_PAGE_ACCESS_TOKEN")
+*/
+func SplitTextMessage(text string) SendRequests {
+	chunks := SplitText(text, MaxTextLength)
+
+	sendRequests := make(SendRequests, len(chunks))
+	for i, chunk := range chunks {
+		sendRequests[i] = TextMessage(chunk)
+	}
+
+	return sendRequests
+}
+
+// To is a fluent helper method for setting Recipient on every request. It is a mutator and
+// returns the same SendRequests on which it is called to support method chaining.
+func (srs SendRequests) To(userId string) SendRequests {
+	for _, sendRequest := range srs {
+		sendRequest.To(userId)
+	}
+
+	return srs
+}
+
+// WithQuickReplies is a fluent helper method for setting QuickReplies on the last request,
+// so that they are shown after the whole sequence has been sent. It is a mutator and returns
+// the same SendRequests on which it is called to support method chaining.
+func (srs SendRequests) WithQuickReplies(replies ...*QuickReply) SendRequests {
+	if len(srs) > 0 {
+		srs[len(srs)-1].WithQuickReplies(replies...)
+	}
+
+	return srs
+}
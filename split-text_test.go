@@ -0,0 +1,70 @@
+package fbmessenger_test
+
+import (
+	. "github.com/ekyoung/fbmessenger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"strings"
+	"unicode/utf8"
+)
+
+var _ = Describe("Split Text", func() {
+	It("should not split text that fits", func() {
+		Expect(SplitText("Hello, world!", 20)).To(Equal([]string{"Hello, world!"}))
+	})
+
+	It("should not return blank chunks", func() {
+		Expect(SplitText("", 20)).To(BeEmpty())
+		Expect(SplitText(strings.Repeat(" ", 5000), 2000)).To(BeEmpty())
+		Expect(SplitText("Hello."+strings.Repeat(" ", 50), 20)).To(Equal([]string{"Hello."}))
+		Expect(SplitTextMessage(strings.Repeat(" ", 5000))).To(BeEmpty())
+	})
+
+	It("should split on sentence boundaries", func() {
+		chunks := SplitText("Hello there. How are you today? Fine thanks.", 35)
+
+		Expect(chunks).To(Equal([]string{"Hello there. How are you today?", "Fine thanks."}))
+	})
+
+	It("should split on word boundaries when there is no sentence boundary", func() {
+		chunks := SplitText("the quick brown fox jumps over the lazy dog", 20)
+
+		Expect(chunks).To(Equal([]string{"the quick brown fox", "jumps over the lazy", "dog"}))
+	})
+
+	It("should split on rune boundaries when there is no word boundary", func() {
+		chunks := SplitText(strings.Repeat("日本語", 5), 4)
+
+		Expect(chunks).To(Equal([]string{"日本語日", "本語日本", "語日本語", "日本語"}))
+		for _, chunk := range chunks {
+			Expect(utf8.ValidString(chunk)).To(BeTrue())
+		}
+	})
+
+	It("should default to the maximum text length", func() {
+		chunks := SplitText(strings.Repeat("word ", 1000), 0)
+
+		Expect(chunks).To(HaveLen(3))
+		for _, chunk := range chunks {
+			Expect(utf8.RuneCountInString(chunk)).To(BeNumerically("<=", MaxTextLength))
+		}
+	})
+
+	It("should create text messages with quick replies on the last one", func() {
+		sendRequests := SplitTextMessage(strings.Repeat("This is a sentence. ", 250)).
+			WithQuickReplies(TextReply("Tell me more", "MORE")).
+			To("USER_ID")
+
+		Expect(sendRequests).To(HaveLen(3))
+		for _, sendRequest := range sendRequests {
+			Expect(sendRequest.Validate()).To(Succeed())
+			Expect(sendRequest.Recipient.Id).To(Equal("USER_ID"))
+		}
+
+		Expect(sendRequests[0].Message.QuickReplies).To(BeEmpty())
+		Expect(sendRequests[1].Message.QuickReplies).To(BeEmpty())
+		Expect(sendRequests[2].Message.QuickReplies).To(HaveLen(1))
+	})
+})
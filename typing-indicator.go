@@ -0,0 +1,66 @@
+package fbmessenger
+
+import (
+	"golang.org/x/net/context"
+	"time"
+)
+
+const (
+	// Facebook turns typing indicators off after 20 seconds, so they are turned on again
+	// before then.
+	typingRefreshInterval = 15 * time.Second
+
+	typingOffTimeout = 5 * time.Second
+)
+
+/*
+WhileTyping shows the user a typing indicator while fn runs. The typing indicator is
+turned on in the background, so fn starts without waiting for Facebook, and is kept on
+until fn returns or ctx is done, and is then turned off. It returns the error returned
+by fn. Errors sending the typing indicator are ignored.
+
+	err := client.WhileTyping(ctx, "USER_ID", "YOUR_PAGE_ACCESS_TOKEN", func(ctx context.Context) error {
+		//Do something slow.
+	})
+*/
+func (c *Client) WhileTyping(ctx context.Context, userId, pageAccessToken string, fn func(ctx context.Context) error) error {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		c.SendSenderActionWithContext(ctx, TypingOn().To(userId), pageAccessToken)
+
+		ticker := time.NewTicker(typingRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.SendSenderActionWithContext(ctx, TypingOn().To(userId), pageAccessToken)
+			case <-ctx.Done():
+				c.turnTypingOff(userId, pageAccessToken)
+				return
+			case <-done:
+				c.turnTypingOff(userId, pageAccessToken)
+				return
+			}
+		}
+	}()
+
+	err := fn(ctx)
+
+	close(done)
+	<-stopped
+
+	return err
+}
+
+// turnTypingOff uses its own context, since the context of the caller may already be done.
+func (c *Client) turnTypingOff(userId, pageAccessToken string) {
+	ctx, cancel := context.WithTimeout(context.Background(), typingOffTimeout)
+	defer cancel()
+
+	c.SendSenderActionWithContext(ctx, TypingOff().To(userId), pageAccessToken)
+}
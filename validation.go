@@ -0,0 +1,205 @@
+package fbmessenger
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits Facebook enforces on the messages it accepts. Lengths are counted in characters.
+const (
+	MaxTextLength               = 2000
+	MaxButtonTemplateTextLength = 640
+	MaxButtons                  = 3
+	MaxGenericElements          = 10
+	MaxQuickReplies             = 11
+	MaxQuickReplyTitleLength    = 20
+)
+
+// Violation describes one rule broken by a request. Field is the path to the field that
+// broke the rule, using its json name, like "message.quick_replies[2].title".
+type Violation struct {
+	Field   string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Field + ": " + v.Message
+}
+
+// ValidationError is returned when a request breaks one or more of the rules Facebook
+// enforces on the messages it accepts.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.String()
+	}
+
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+/*
+Validate checks the request against the rules Facebook enforces, so that a request that
+would be rejected can be caught before it is sent. A *ValidationError listing every
+violation is returned if any rule is broken.
+
+	err := request.Validate()
+	if validationErr, ok := err.(*fbmessenger.ValidationError); ok {
+		for _, violation := range validationErr.Violations {
+			//violation.Field is like "message.attachment.payload.buttons"
+		}
+	}
+
+Set ValidateRequests on a Client to validate each request before it is sent.
+*/
+func (sr *SendRequest) Validate() error {
+	var violations violations
+
+	switch {
+	case sr.Recipient.Id == "" && sr.Recipient.PhoneNumber == "":
+		violations.add("recipient", "must have an id or a phone_number")
+	case sr.Recipient.Id != "" && sr.Recipient.PhoneNumber != "":
+		violations.add("recipient", "must have an id or a phone_number, but not both")
+	}
+
+	violations.addMessage("message", &sr.Message)
+
+	return violations.err()
+}
+
+// Validate checks that the list has 2 to 4 elements, and at most one button for the list
+// and for each element. A *ValidationError listing every violation is returned if not.
+func (lp *ListPayload) Validate() error {
+	var violations violations
+
+	violations.addListPayload("", lp)
+
+	return violations.err()
+}
+
+type violations []Violation
+
+func (vs *violations) add(field, format string, args ...interface{}) {
+	*vs = append(*vs, Violation{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (vs violations) err() error {
+	if len(vs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: vs}
+}
+
+func (vs *violations) addMessage(path string, message *Message) {
+	hasText := message.Text != ""
+	hasAttachment := message.Attachment != nil
+
+	if hasText == hasAttachment {
+		vs.add(path, "must have either text or an attachment, but not both")
+	}
+
+	if length := utf8.RuneCountInString(message.Text); length > MaxTextLength {
+		vs.add(field(path, "text"), "must be at most %v characters, has %v", MaxTextLength, length)
+	}
+
+	if hasAttachment {
+		vs.addPayload(field(path, "attachment.payload"), message.Attachment.Payload)
+	}
+
+	quickRepliesPath := field(path, "quick_replies")
+	if len(message.QuickReplies) > MaxQuickReplies {
+		vs.add(quickRepliesPath, "must have at most %v quick replies, has %v", MaxQuickReplies, len(message.QuickReplies))
+	}
+
+	for i, quickReply := range message.QuickReplies {
+		if length := utf8.RuneCountInString(quickReply.Title); length > MaxQuickReplyTitleLength {
+			vs.add(index(quickRepliesPath, i, "title"), "must be at most %v characters, has %v", MaxQuickReplyTitleLength, length)
+		}
+	}
+}
+
+func (vs *violations) addPayload(path string, payload interface{}) {
+	switch payload := payload.(type) {
+	case ButtonPayload:
+		vs.addButtonPayload(path, &payload)
+	case *ButtonPayload:
+		vs.addButtonPayload(path, payload)
+	case GenericPayload:
+		vs.addGenericPayload(path, &payload)
+	case *GenericPayload:
+		vs.addGenericPayload(path, payload)
+	case ListPayload:
+		vs.addListPayload(path, &payload)
+	case *ListPayload:
+		vs.addListPayload(path, payload)
+	}
+}
+
+func (vs *violations) addButtonPayload(path string, payload *ButtonPayload) {
+	switch length := utf8.RuneCountInString(payload.Text); {
+	case length == 0:
+		vs.add(field(path, "text"), "is required")
+	case length > MaxButtonTemplateTextLength:
+		vs.add(field(path, "text"), "must be at most %v characters, has %v", MaxButtonTemplateTextLength, length)
+	}
+
+	if len(payload.Buttons) == 0 || len(payload.Buttons) > MaxButtons {
+		vs.add(field(path, "buttons"), "must have 1 to %v buttons, has %v", MaxButtons, len(payload.Buttons))
+	}
+}
+
+func (vs *violations) addGenericPayload(path string, payload *GenericPayload) {
+	elementsPath := field(path, "elements")
+	if len(payload.Elements) == 0 || len(payload.Elements) > MaxGenericElements {
+		vs.add(elementsPath, "must have 1 to %v elements, has %v", MaxGenericElements, len(payload.Elements))
+	}
+
+	for i, element := range payload.Elements {
+		if element.Title == "" {
+			vs.add(index(elementsPath, i, "title"), "is required")
+		}
+
+		if len(element.Buttons) > MaxButtons {
+			vs.add(index(elementsPath, i, "buttons"), "must have at most %v buttons, has %v", MaxButtons, len(element.Buttons))
+		}
+	}
+}
+
+func (vs *violations) addListPayload(path string, payload *ListPayload) {
+	elementsPath := field(path, "elements")
+	if len(payload.Elements) < 2 || len(payload.Elements) > 4 {
+		vs.add(elementsPath, "must have 2 to 4 elements, has %v", len(payload.Elements))
+	}
+
+	if len(payload.Buttons) > 1 {
+		vs.add(field(path, "buttons"), "must have at most 1 button, has %v", len(payload.Buttons))
+	}
+
+	for i, element := range payload.Elements {
+		if len(element.Buttons) > 1 {
+			vs.add(index(elementsPath, i, "buttons"), "must have at most 1 button, has %v", len(element.Buttons))
+		}
+	}
+}
+
+// field joins the name of a field to the path of the struct holding it.
+func field(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// index returns the path to the named field of the i-th item of the slice at path.
+func index(path string, i int, name string) string {
+	return field(fmt.Sprintf("%v[%v]", path, i), name)
+}
@@ -0,0 +1,120 @@
+package fbmessenger_test
+
+import (
+	. "github.com/ekyoung/fbmessenger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"strings"
+)
+
+var _ = Describe("Validation", func() {
+	violationsOf := func(sendRequest *SendRequest) []Violation {
+		err := sendRequest.Validate()
+		if err == nil {
+			return nil
+		}
+
+		validationErr, ok := err.(*ValidationError)
+		Expect(ok).To(BeTrue())
+
+		return validationErr.Violations
+	}
+
+	fields := func(violations []Violation) []string {
+		result := make([]string, len(violations))
+		for i, violation := range violations {
+			result[i] = violation.Field
+		}
+
+		return result
+	}
+
+	It("should accept a valid request", func() {
+		sendRequest := TextMessage("Hello, world!").
+			WithQuickReplies(TextReply("Yes", "YES"), TextReply("No", "NO")).
+			To("USER_ID")
+
+		Expect(sendRequest.Validate()).To(Succeed())
+	})
+
+	It("should require a recipient", func() {
+		Expect(fields(violationsOf(TextMessage("Hello, world!")))).To(Equal([]string{"recipient"}))
+	})
+
+	It("should require either text or an attachment, but not both", func() {
+		sendRequest := ImageMessage("http://example.com/image.png").To("USER_ID")
+		sendRequest.Message.Text = "Hello, world!"
+
+		Expect(fields(violationsOf(sendRequest))).To(Equal([]string{"message"}))
+		Expect(fields(violationsOf(TextMessage("").To("USER_ID")))).To(Equal([]string{"message"}))
+	})
+
+	It("should limit the length of text in characters", func() {
+		Expect(TextMessage(strings.Repeat("é", 2000)).To("USER_ID").Validate()).To(Succeed())
+
+		violations := violationsOf(TextMessage(strings.Repeat("a", 2001)).To("USER_ID"))
+
+		Expect(fields(violations)).To(Equal([]string{"message.text"}))
+		Expect(violations[0].Message).To(Equal("must be at most 2000 characters, has 2001"))
+	})
+
+	It("should limit the text and buttons of a button template", func() {
+		button := PostbackButton("Start Chatting", "USER_DEFINED_PAYLOAD")
+		sendRequest := ButtonTemplateMessage(strings.Repeat("a", 641), button, button, button, button).To("USER_ID")
+
+		Expect(fields(violationsOf(sendRequest))).To(Equal([]string{
+			"message.attachment.payload.text",
+			"message.attachment.payload.buttons",
+		}))
+	})
+
+	It("should limit the elements and buttons of a generic template", func() {
+		button := PostbackButton("Start Chatting", "USER_DEFINED_PAYLOAD")
+		elements := make([]*GenericElement, 11)
+		for i := range elements {
+			elements[i] = NewGenericElement("Classic White T-Shirt")
+		}
+		elements[3].WithButtons(button, button, button, button)
+
+		Expect(fields(violationsOf(GenericTemplateMessage(elements...).To("USER_ID")))).To(Equal([]string{
+			"message.attachment.payload.elements",
+			"message.attachment.payload.elements[3].buttons",
+		}))
+	})
+
+	It("should validate a list template", func() {
+		element := NewListElement("Classic T-Shirt Collection")
+		element.Buttons = []*Button{
+			URLButton("View", "https://peterssendreceiveapp.ngrok.io/collection"),
+			URLButton("Shop Now", "https://peterssendreceiveapp.ngrok.io/shop"),
+		}
+
+		Expect(fields(violationsOf(ListTemplateMessage(element).To("USER_ID")))).To(Equal([]string{
+			"message.attachment.payload.elements",
+			"message.attachment.payload.elements[0].buttons",
+		}))
+	})
+
+	It("should limit the number and titles of quick replies", func() {
+		quickReplies := make([]*QuickReply, 12)
+		for i := range quickReplies {
+			quickReplies[i] = TextReply("Red", "RED")
+		}
+		quickReplies[5] = TextReply("A title that is much too long", "LONG")
+
+		sendRequest := TextMessage("Pick a color:").WithQuickReplies(quickReplies...).To("USER_ID")
+
+		Expect(fields(violationsOf(sendRequest))).To(Equal([]string{
+			"message.quick_replies",
+			"message.quick_replies[5].title",
+		}))
+	})
+
+	It("should describe every violation in the error message", func() {
+		err := TextMessage("").Validate()
+
+		Expect(err.Error()).To(Equal("invalid request: recipient: must have an id or a phone_number; message: must have either text or an attachment, but not both"))
+	})
+})
@@ -0,0 +1,99 @@
+package fbmessenger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// DefaultMaxBodyBytes is the largest request body read from a callback when no other
+// limit is set.
+const DefaultMaxBodyBytes = 1 << 20
+
+/*
+WebhookHandler is an http.Handler for your webhook endpoint. It answers the verification
+request Facebook sends when you subscribe your webhook, and routes each callback POSTed
+to the endpoint through a CallbackDispatcher. The context of the request is passed
+through to each handler.
+
+	handler := &fbmessenger.WebhookHandler{
+		VerifyToken: "YOUR_VERIFY_TOKEN",
+		Dispatcher: &fbmessenger.CallbackDispatcher{
+			MessageHandler: MessageReceived,
+		},
+	}
+
+	http.Handle("/webhook", handler)
+
+Callbacks with a body larger than MaxBodyBytes (default DefaultMaxBodyBytes) are rejected
+with status code 413 (Request Entity Too Large) without being read in full.
+
+See https://developers.facebook.com/docs/messenger-platform/webhook-reference#setup
+*/
+type WebhookHandler struct {
+	VerifyToken  string
+	Dispatcher   *CallbackDispatcher
+	MaxBodyBytes int64
+}
+
+// ServeHTTP responds to verification requests (GET) and callbacks (POST).
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		h.verify(w, r)
+	case "POST":
+		h.dispatch(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *WebhookHandler) verify(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Get("hub.mode") != "subscribe" || h.VerifyToken == "" || query.Get("hub.verify_token") != h.VerifyToken {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(query.Get("hub.challenge")))
+}
+
+func (h *WebhookHandler) dispatch(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, maxBodyBytes(h.MaxBodyBytes))
+
+	cb := &Callback{}
+	err := json.NewDecoder(body).Decode(cb)
+	if isBodyTooLarge(err) {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	} else if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if h.Dispatcher != nil {
+		err = h.Dispatcher.DispatchWithContext(r.Context(), cb)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func maxBodyBytes(limit int64) int64 {
+	if limit <= 0 {
+		return DefaultMaxBodyBytes
+	}
+
+	return limit
+}
+
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
@@ -0,0 +1,126 @@
+package fbmessenger_test
+
+import (
+	. "github.com/ekyoung/fbmessenger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+var _ = Describe("WebhookHandler", func() {
+	const verifyToken = "VERIFY_TOKEN"
+
+	var (
+		messageHandlerCalls int
+		messageHandlerError error
+
+		handler *WebhookHandler
+	)
+
+	BeforeEach(func() {
+		messageHandlerCalls = 0
+		messageHandlerError = nil
+
+		handler = &WebhookHandler{
+			VerifyToken: verifyToken,
+			Dispatcher: &CallbackDispatcher{
+				MessageHandler: func(entry *MessagingEntry) error {
+					messageHandlerCalls++
+					return messageHandlerError
+				},
+			},
+		}
+	})
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	Describe("Verification", func() {
+		It("should respond with the challenge when the verify token matches", func() {
+			req := httptest.NewRequest("GET", "/webhook?hub.mode=subscribe&hub.verify_token="+verifyToken+"&hub.challenge=CHALLENGE", nil)
+
+			recorder := serve(req)
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("CHALLENGE"))
+		})
+
+		It("should respond with forbidden when the verify token does not match", func() {
+			req := httptest.NewRequest("GET", "/webhook?hub.mode=subscribe&hub.verify_token=WRONG&hub.challenge=CHALLENGE", nil)
+
+			recorder := serve(req)
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(recorder.Body.String()).ToNot(ContainSubstring("CHALLENGE"))
+		})
+
+		It("should respond with forbidden when the mode is not subscribe", func() {
+			req := httptest.NewRequest("GET", "/webhook?hub.mode=unsubscribe&hub.verify_token="+verifyToken+"&hub.challenge=CHALLENGE", nil)
+
+			recorder := serve(req)
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Callbacks", func() {
+		It("should dispatch a callback and respond with ok", func() {
+			req := httptest.NewRequest("POST", "/webhook", callbackBody(createMessageCallback()))
+
+			recorder := serve(req)
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(messageHandlerCalls).To(Equal(1))
+		})
+
+		It("should respond with bad request when the body is not valid json", func() {
+			req := httptest.NewRequest("POST", "/webhook", strings.NewReader("{not json"))
+
+			recorder := serve(req)
+
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(messageHandlerCalls).To(Equal(0))
+		})
+
+		It("should respond with request entity too large when the body is over the limit", func() {
+			handler.MaxBodyBytes = 64
+			req := httptest.NewRequest("POST", "/webhook", callbackBody(createMessageCallback()))
+
+			recorder := serve(req)
+
+			Expect(recorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			Expect(messageHandlerCalls).To(Equal(0))
+		})
+
+		It("should respond with internal server error when a handler fails", func() {
+			messageHandlerError = errors.New("handler failed")
+			req := httptest.NewRequest("POST", "/webhook", callbackBody(createMessageCallback()))
+
+			recorder := serve(req)
+
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	It("should respond with method not allowed for other methods", func() {
+		req := httptest.NewRequest("PUT", "/webhook", nil)
+
+		recorder := serve(req)
+
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
+
+func callbackBody(cb *Callback) *strings.Reader {
+	cbBytes, _ := json.Marshal(cb)
+	return strings.NewReader(string(cbBytes))
+}